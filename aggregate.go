@@ -0,0 +1,285 @@
+package table
+
+import (
+	"cmp"
+	"math"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// Aggregate computes the footer value of a column from the values of its
+// cells. Invalid (nil) values are not passed to the Aggregate. The footer
+// label "TOTAL" is shown in the first column if it has no Aggregate.
+type Aggregate func(values []reflect.Value) any
+
+// footerLabel is printed in the first footer cell if its column has no
+// Aggregate.
+const footerLabel = "TOTAL"
+
+// aggregates are the Aggregates of the "table" struct tag options. If a tag has
+// more than one, the first in this order is used.
+var aggregates = []struct {
+	name string
+	fn   Aggregate
+}{
+	{"sum", Sum},
+	{"avg", Avg},
+	{"min", Min},
+	{"max", Max},
+	{"count", Count},
+}
+
+// columnFooter is the Aggregate given to WithFooter for a column.
+type columnFooter struct {
+	column string
+	fn     Aggregate
+}
+
+// WithFooter is an option setting function for New. It adds a footer row
+// showing the aggregate a of the column with the header label or struct field
+// name column. This is the same as the "sum", "avg", "min", "max" and "count"
+// "table" struct tag options, but can use any Aggregate. If more than one
+// WithFooter matches a column, the last one is used. JSON and YAML only
+// include the footer with WithEnvelope, as the totals of each section.
+func WithFooter(column string, a Aggregate) func(*Table) {
+	return func(t *Table) {
+		t.footers = append(t.footers, columnFooter{column: column, fn: a})
+	}
+}
+
+// Sum returns the sum of numeric values. The sum of integers has the type of
+// the first value, so a sum of time.Duration is a time.Duration. It returns nil
+// if the values are not all signed integers, unsigned integers or floats.
+func Sum(values []reflect.Value) any {
+	if len(values) == 0 {
+		return nil
+	}
+
+	typ := values[0].Type()
+
+	switch {
+	case values[0].CanInt():
+		var sum int64
+
+		for _, v := range values {
+			if !v.CanInt() {
+				return nil
+			}
+
+			sum += v.Int()
+		}
+
+		return convertInt(typ, sum)
+	case values[0].CanUint():
+		var sum uint64
+
+		for _, v := range values {
+			if !v.CanUint() {
+				return nil
+			}
+
+			sum += v.Uint()
+		}
+
+		return convertUint(typ, sum)
+	case values[0].CanFloat():
+		var sum float64
+
+		for _, v := range values {
+			if !v.CanFloat() {
+				return nil
+			}
+
+			sum += v.Float()
+		}
+
+		return sum
+	default:
+		return nil
+	}
+}
+
+// Avg returns the mean of numeric values as a float64, or as a time.Duration
+// rounded to the nearest nanosecond if the values are durations.
+func Avg(values []reflect.Value) any {
+	var sum float64
+
+	for _, v := range values {
+		switch {
+		case v.CanInt():
+			sum += float64(v.Int())
+		case v.CanUint():
+			sum += float64(v.Uint())
+		case v.CanFloat():
+			sum += v.Float()
+		default:
+			return nil
+		}
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	mean := sum / float64(len(values))
+
+	if values[0].Type() == reflect.TypeFor[time.Duration]() {
+		return time.Duration(math.Round(mean))
+	}
+
+	return mean
+}
+
+// Min returns the smallest of numbers, strings or times.
+func Min(values []reflect.Value) any {
+	return extreme(values, -1)
+}
+
+// Max returns the largest of numbers, strings or times.
+func Max(values []reflect.Value) any {
+	return extreme(values, 1)
+}
+
+// Count returns the number of values that are not zero.
+func Count(values []reflect.Value) any {
+	var n int
+
+	for _, v := range values {
+		if !v.IsZero() {
+			n++
+		}
+	}
+
+	return n
+}
+
+// extreme returns the value v where compareValues(v, other) has the sign of
+// dir for all other values.
+func extreme(values []reflect.Value, dir int) any {
+	var best reflect.Value
+
+	for _, v := range values {
+		if !best.IsValid() || cmp.Compare(compareValues(v, best), 0) == dir {
+			best = v
+		}
+	}
+
+	if !best.IsValid() || !best.CanInterface() {
+		return nil
+	}
+
+	return best.Interface()
+}
+
+// compareValues compares numbers, strings and times by value. Values of other
+// kinds are compared by their text.
+func compareValues(a, b reflect.Value) int {
+	if a.IsValid() && b.IsValid() && a.CanInterface() && b.CanInterface() {
+		ta, okA := a.Interface().(time.Time)
+		tb, okB := b.Interface().(time.Time)
+
+		if okA && okB {
+			return ta.Compare(tb)
+		}
+	}
+
+	switch {
+	case a.CanInt() && b.CanInt():
+		return cmp.Compare(a.Int(), b.Int())
+	case a.CanUint() && b.CanUint():
+		return cmp.Compare(a.Uint(), b.Uint())
+	case a.CanFloat() && b.CanFloat():
+		return cmp.Compare(a.Float(), b.Float())
+	case a.Kind() == reflect.String && b.Kind() == reflect.String:
+		return cmp.Compare(a.String(), b.String())
+	default:
+		return cmp.Compare(valueAsString(a), valueAsString(b))
+	}
+}
+
+// convertInt returns n as type typ, or as an int64 if it does not fit.
+func convertInt(typ reflect.Type, n int64) any {
+	v := reflect.New(typ).Elem()
+	if v.OverflowInt(n) {
+		return n
+	}
+
+	v.SetInt(n)
+
+	return v.Interface()
+}
+
+// convertUint returns n as type typ, or as a uint64 if it does not fit.
+func convertUint(typ reflect.Type, n uint64) any {
+	v := reflect.New(typ).Elem()
+	if v.OverflowUint(n) {
+		return n
+	}
+
+	v.SetUint(n)
+
+	return v.Interface()
+}
+
+// footer computes the footer cells of the columns with an Aggregate, or
+// returns nil if there are none. The label is shown in the first column if it
+// has no Aggregate.
+func footer(info []Column, rows [][]Cell, label string) []Cell {
+	var cells []Cell
+
+	for j := range info {
+		if info[j].Aggregate == nil {
+			continue
+		}
+
+		if cells == nil {
+			cells = make([]Cell, len(info))
+		}
+
+		var values []reflect.Value
+
+		for i := range rows {
+			if v := rows[i][j].Value; v.IsValid() && v.CanInterface() {
+				values = append(values, v)
+			}
+		}
+
+		value := reflect.ValueOf(info[j].Aggregate(values))
+		cells[j] = Cell{Text: info[j].text(value), Value: value}
+
+		info[j].Width = max(info[j].Width, displayWidth(cells[j].Text))
+	}
+
+	if cells == nil {
+		return nil
+	}
+
+	if j := firstVisible(info); j >= 0 && info[j].Aggregate == nil {
+		cells[j].Text = label
+		info[j].Width = max(info[j].Width, displayWidth(label))
+	}
+
+	return cells
+}
+
+// ungroupedFooter returns footer with its label moved from the first visible
+// column to the first column that is not empty, for the formats that show the
+// grouped columns.
+func ungroupedFooter(info []Column, footer []Cell) []Cell {
+	from := firstVisible(info)
+	to := slices.IndexFunc(info, func(c Column) bool { return !c.IsZero })
+
+	if from < 0 || from == to || info[from].Aggregate != nil {
+		return footer
+	}
+
+	cells := slices.Clone(footer)
+	if info[to].Aggregate == nil {
+		cells[to].Text = cells[from].Text
+	}
+
+	cells[from].Text = ""
+
+	return cells
+}
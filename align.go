@@ -0,0 +1,133 @@
+package table
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Alignment is the horizontal alignment of the text in a column.
+type Alignment int
+
+// Column alignments. AlignAuto is left aligned text unless numeric alignment is
+// enabled with WithNumericAlignment. AlignDecimal right aligns the text with
+// the decimal points lined up.
+const (
+	AlignAuto Alignment = iota
+	AlignLeft
+	AlignRight
+	AlignCenter
+	AlignDecimal
+)
+
+// WithNumericAlignment is an option setting function for New. It right aligns
+// integer columns and decimal aligns floating point columns, unless the column
+// has an "align" option in its "table" struct tag.
+func WithNumericAlignment() func(*Table) {
+	return func(t *Table) {
+		t.alignNumbers = true
+	}
+}
+
+func parseAlignment(s string) Alignment {
+	switch s {
+	case "left":
+		return AlignLeft
+	case "right":
+		return AlignRight
+	case "center":
+		return AlignCenter
+	case "decimal":
+		return AlignDecimal
+	default:
+		return AlignAuto
+	}
+}
+
+// numericAlignment returns the alignment for c if it is a numeric column.
+func numericAlignment(c Column) Alignment {
+	switch {
+	case c.Kind == reflect.Float32 || c.Kind == reflect.Float64:
+		return AlignDecimal
+	case c.isNumeric():
+		return AlignRight
+	default:
+		return AlignAuto
+	}
+}
+
+// alignDecimals sizes the integer and fractional parts of the decimal aligned
+// columns, widening the columns if needed.
+func alignDecimals(info []Column, rows [][]Cell) {
+	for j := range info {
+		if info[j].Align != AlignDecimal {
+			continue
+		}
+
+		for i := range rows {
+			if rows[i][j].Text == "" {
+				continue
+			}
+
+			whole, frac := splitDecimal(rows[i][j].Text, info[j].decimalMark())
+			info[j].intWidth = max(info[j].intWidth, displayWidth(whole))
+			info[j].fracWidth = max(info[j].fracWidth, displayWidth(frac))
+		}
+
+		info[j].Width = max(info[j].Width, info[j].intWidth+info[j].fracWidth)
+	}
+}
+
+// padding returns the spaces needed on either side of the text of a cell to
+// fill the column. Decimal aligned text that was shortened to fit the column
+// is right aligned.
+func (c Column) padding(text string) (left, right string) {
+	n := max(c.Width-displayWidth(text), 0)
+
+	if c.Align == AlignDecimal {
+		_, frac := splitDecimal(text, c.decimalMark())
+		r := c.fracWidth - displayWidth(frac)
+
+		if r < 0 || r > n {
+			return pad(AlignRight, n)
+		}
+
+		return strings.Repeat(" ", max(n-r, 0)), strings.Repeat(" ", max(r, 0))
+	}
+
+	return pad(c.Align, n)
+}
+
+// headerPadding returns the spaces needed on either side of label to fill the
+// column.
+func (c Column) headerPadding(label string) (left, right string) {
+	a := c.Align
+	if a == AlignDecimal {
+		a = AlignRight
+	}
+
+	return pad(a, c.Width-displayWidth(label))
+}
+
+// pad splits n spaces to either side of the text for the alignment a.
+func pad(a Alignment, n int) (left, right string) {
+	n = max(n, 0)
+
+	switch a {
+	case AlignRight, AlignDecimal:
+		return strings.Repeat(" ", n), ""
+	case AlignCenter:
+		return strings.Repeat(" ", n/2), strings.Repeat(" ", n-n/2)
+	default:
+		return "", strings.Repeat(" ", n)
+	}
+}
+
+// splitDecimal splits a formatted number at its decimal mark. The fractional
+// part includes the mark.
+func splitDecimal(s, mark string) (whole, frac string) {
+	if i := strings.LastIndex(s, mark); i >= 0 {
+		return s[:i], s[i:]
+	}
+
+	return s, ""
+}
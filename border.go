@@ -0,0 +1,207 @@
+package table
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Border is the set of strings used to draw the lines of a table. The names
+// of the corners and junctions follow their position in the table.
+type Border struct {
+	Horizontal   string
+	Vertical     string
+	TopLeft      string
+	TopMiddle    string
+	TopRight     string
+	MiddleLeft   string
+	Middle       string
+	MiddleRight  string
+	BottomLeft   string
+	BottomMiddle string
+	BottomRight  string
+	Outer        bool // Outer draws the outside frame of the table.
+}
+
+// Border presets for WithBorder.
+var (
+	BorderLight = Border{
+		Horizontal: "─", Vertical: "│",
+		TopLeft: "┌", TopMiddle: "┬", TopRight: "┐",
+		MiddleLeft: "├", Middle: "┼", MiddleRight: "┤",
+		BottomLeft: "└", BottomMiddle: "┴", BottomRight: "┘",
+		Outer: true,
+	}
+	BorderHeavy = Border{
+		Horizontal: "━", Vertical: "┃",
+		TopLeft: "┏", TopMiddle: "┳", TopRight: "┓",
+		MiddleLeft: "┣", Middle: "╋", MiddleRight: "┫",
+		BottomLeft: "┗", BottomMiddle: "┻", BottomRight: "┛",
+		Outer: true,
+	}
+	BorderDouble = Border{
+		Horizontal: "═", Vertical: "║",
+		TopLeft: "╔", TopMiddle: "╦", TopRight: "╗",
+		MiddleLeft: "╠", Middle: "╬", MiddleRight: "╣",
+		BottomLeft: "╚", BottomMiddle: "╩", BottomRight: "╝",
+		Outer: true,
+	}
+	BorderRounded = Border{
+		Horizontal: "─", Vertical: "│",
+		TopLeft: "╭", TopMiddle: "┬", TopRight: "╮",
+		MiddleLeft: "├", Middle: "┼", MiddleRight: "┤",
+		BottomLeft: "╰", BottomMiddle: "┴", BottomRight: "╯",
+		Outer: true,
+	}
+	BorderASCII = Border{
+		Horizontal: "-", Vertical: "|",
+		TopLeft: "+", TopMiddle: "+", TopRight: "+",
+		MiddleLeft: "+", Middle: "+", MiddleRight: "+",
+		BottomLeft: "+", BottomMiddle: "+", BottomRight: "+",
+		Outer: true,
+	}
+	BorderMinimal = Border{
+		Horizontal: "-", Vertical: "|",
+		Middle: "+",
+	}
+)
+
+// noBorder draws the rules of a table without a border.
+var noBorder = Border{
+	Horizontal: "-",
+	TopMiddle:  " ", Middle: " ", BottomMiddle: " ",
+}
+
+type ruleKind int
+
+const (
+	ruleTop ruleKind = iota
+	ruleMiddle
+	ruleBottom
+)
+
+// WithBorder is an option setting function for New. It draws the text output
+// with column separators and a rule under the header using the strings of b.
+// If b has an Outer border the table is framed.
+func WithBorder(b Border) func(*Table) {
+	return func(t *Table) {
+		t.border = &b
+	}
+}
+
+// WithRowRules is an option setting function for New. It draws a horizontal
+// rule after every n rows of the text output.
+func WithRowRules(n int) func(*Table) {
+	return func(t *Table) {
+		t.rowRules = n
+	}
+}
+
+// WithSectionRules is an option setting function for New. It draws a
+// horizontal rule between the tables of the text output that FlushText starts
+// when the struct type changes.
+func WithSectionRules() func(*Table) {
+	return func(t *Table) {
+		t.sectionRules = true
+	}
+}
+
+// separatorWidth returns the width between two columns.
+func (t *Table) separatorWidth() int {
+	if t.border == nil {
+		return 1
+	}
+
+	return displayWidth(t.border.Vertical) + 2
+}
+
+// frameWidth returns the width of the left and right sides of the border.
+func (t *Table) frameWidth() int {
+	if t.border == nil || !t.border.Outer {
+		return 0
+	}
+
+	return 2 * (displayWidth(t.border.Vertical) + 1)
+}
+
+// writeRule writes a horizontal line across the visible columns.
+func (t *Table) writeRule(w io.Writer, info []Column, kind ruleKind) {
+	b := noBorder
+	if t.border != nil {
+		b = *t.border
+	}
+
+	left, middle, right := b.MiddleLeft, b.Middle, b.MiddleRight
+
+	switch kind {
+	case ruleTop:
+		left, middle, right = b.TopLeft, b.TopMiddle, b.TopRight
+	case ruleBottom:
+		left, middle, right = b.BottomLeft, b.BottomMiddle, b.BottomRight
+	case ruleMiddle:
+	}
+
+	var (
+		rule  strings.Builder
+		first = true
+		last  = lastVisible(info)
+	)
+
+	if b.Outer {
+		rule.WriteString(left)
+	}
+
+	for j := range info {
+		if !info[j].visible() {
+			continue
+		}
+
+		if !first {
+			rule.WriteString(middle)
+		}
+
+		n := info[j].Width
+
+		if t.border != nil { // the rule covers the padding around the separators
+			if b.Outer || !first {
+				n++
+			}
+
+			if b.Outer || j != last {
+				n++
+			}
+		}
+
+		first = false
+
+		rule.WriteString(strings.Repeat(b.Horizontal, n))
+	}
+
+	if b.Outer {
+		rule.WriteString(right)
+	}
+
+	fmt.Fprintln(w, rule.String())
+}
+
+// firstVisible returns the index of the first visible column.
+func firstVisible(info []Column) int {
+	for j := range info {
+		if info[j].visible() {
+			return j
+		}
+	}
+
+	return -1
+}
+
+// lastVisible returns the index of the last visible column.
+func lastVisible(info []Column) int {
+	for j := len(info) - 1; j >= 0; j-- {
+		if info[j].visible() {
+			return j
+		}
+	}
+
+	return -1
+}
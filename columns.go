@@ -0,0 +1,100 @@
+package table
+
+import "slices"
+
+// WithColumns is an option setting function for New. It selects the columns to
+// print, in the given order, by header label or struct field name. Names that
+// do not match a column are ignored, and a table with none of the columns is
+// printed in full. The selection applies to every output format, so it implies
+// WithColumnEncoding.
+func WithColumns(columns ...string) func(*Table) {
+	return func(t *Table) {
+		t.byColumn = true
+		t.columns = columns
+	}
+}
+
+// WithoutColumns is an option setting function for New. It hides the columns
+// with the given header labels or struct field names in every output format.
+// It implies WithColumnEncoding.
+func WithoutColumns(columns ...string) func(*Table) {
+	return func(t *Table) {
+		t.byColumn = true
+		t.hiddenColumns = columns
+	}
+}
+
+// WithWide is an option setting function for New. It shows the columns with the
+// "wide" option in their "table" struct tag, like the "-o wide" output of
+// kubectl. It is the same as WithTier("wide").
+func WithWide() func(*Table) {
+	return WithTier("wide")
+}
+
+// WithTier is an option setting function for New. It shows the columns with a
+// "tier=name" option in their "table" struct tag for each of the names. These
+// columns are hidden by default in every output format, so JSON and YAML use
+// WithColumnEncoding for structs with tier columns, whether they are shown or
+// not. Columns selected by WithColumns are shown regardless of their tier.
+func WithTier(names ...string) func(*Table) {
+	return func(t *Table) {
+		t.tiers = append(t.tiers, names...)
+	}
+}
+
+// selectColumns removes the hidden columns of s and puts the rest in the order
+// given to WithColumns.
+func (t *Table) selectColumns(s *Section) {
+	if t.columns == nil && t.hiddenColumns == nil && !slices.ContainsFunc(s.Columns, t.hiddenTier) {
+		return
+	}
+
+	var keep []int
+
+	for _, name := range t.columns {
+		for j := range s.Columns {
+			if s.Columns[j].matches(name) && !slices.Contains(keep, j) {
+				keep = append(keep, j)
+			}
+		}
+	}
+
+	if len(keep) == 0 {
+		for j := range s.Columns {
+			if !t.hiddenTier(s.Columns[j]) {
+				keep = append(keep, j)
+			}
+		}
+	}
+
+	keep = slices.DeleteFunc(keep, func(j int) bool {
+		return slices.ContainsFunc(t.hiddenColumns, s.Columns[j].matches)
+	})
+
+	columns := make([]Column, len(keep))
+	for k, j := range keep {
+		columns[k] = s.Columns[j]
+	}
+
+	s.Columns = columns
+
+	for i, row := range s.Cells {
+		cells := make([]Cell, len(keep))
+		for k, j := range keep {
+			cells[k] = row[j]
+		}
+
+		s.Cells[i] = cells
+	}
+}
+
+// hiddenTier returns true if the column is in a tier that is not shown.
+func (t *Table) hiddenTier(c Column) bool {
+	return c.tier != "" && !slices.Contains(t.tiers, c.tier)
+}
+
+// hasTier returns true if the struct type of s has a column in a tier, shown or
+// not, so that its JSON and YAML keys do not depend on the tiers shown.
+func (t *Table) hasTier(s Section) bool {
+	return slices.ContainsFunc(t.processHeader(s.Type), func(c Column) bool { return c.tier != "" })
+}
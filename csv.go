@@ -0,0 +1,96 @@
+package table
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+// AsCSV is an option setting function for New. It sets CSV as the default
+// output format for Flush.
+func AsCSV() func(*Table) {
+	return func(t *Table) {
+		t.format = FormatCSV
+	}
+}
+
+// AsTSV is an option setting function for New. It sets TSV as the default
+// output format for Flush.
+func AsTSV() func(*Table) {
+	return func(t *Table) {
+		t.format = FormatTSV
+	}
+}
+
+// FlushCSV flushes the Table data to its io.Writer as comma separated values.
+// The header record uses the same labels as the text output, and omitempty
+// columns are dropped. A blank line separates the tables of different struct
+// types.
+func (t *Table) FlushCSV() error {
+	if t.err != nil {
+		return t.err
+	}
+
+	return t.encodeCSV(t.writer, t.sections())
+}
+
+// FlushTSV flushes the Table data to its io.Writer as tab separated values. It
+// is otherwise identical to FlushCSV.
+func (t *Table) FlushTSV() error {
+	if t.err != nil {
+		return t.err
+	}
+
+	return t.encodeTSV(t.writer, t.sections())
+}
+
+func (*Table) encodeCSV(w io.Writer, sections []Section) error {
+	return encodeDelimited(w, sections, ',')
+}
+
+func (*Table) encodeTSV(w io.Writer, sections []Section) error {
+	return encodeDelimited(w, sections, '\t')
+}
+
+func encodeDelimited(out io.Writer, sections []Section, comma rune) error {
+	w := csv.NewWriter(out)
+	w.Comma = comma
+
+	for i, s := range sections {
+		if i > 0 {
+			if err := w.Write(nil); err != nil {
+				return err
+			}
+		}
+
+		header := make([]string, 0, len(s.Columns))
+
+		for _, c := range s.Columns {
+			if !c.IsZero {
+				header = append(header, strings.Join(c.Labels, " "))
+			}
+		}
+
+		if err := w.Write(header); err != nil {
+			return err
+		}
+
+		for _, row := range s.Cells {
+			record := make([]string, 0, len(row))
+
+			for j := range row {
+				if !s.Columns[j].IsZero {
+					record = append(record, row[j].Text)
+				}
+			}
+
+			if err := w.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	w.Flush()
+
+	return w.Error()
+}
@@ -0,0 +1,389 @@
+package table
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrFilter is returned from Flush if the expression given to WithFilter is
+// invalid.
+var ErrFilter = errors.New("invalid filter")
+
+// WithFilter is an option setting function for New. It only keeps the rows for
+// which the expression is true, in all output formats. Expressions compare
+// columns, named by header label or struct field name, with a value:
+//
+//	STATUS == "running" && PORT >= 8000 || HOST =~ "^compute-"
+//
+// The operators are ==, !=, <, <=, >, >=, =~ and !~ (regular expression match
+// of the cell text), combined with &&, ||, ! and parentheses. Values are
+// converted to the type of the field, so numbers, times (RFC 3339 or
+// 2006-01-02), durations (5m) and booleans compare by value. Values can be
+// quoted and must be if they contain spaces or operator characters. A column
+// without a comparison, such as !DONE, is true if its value is not zero. A row
+// without the column, or with a value that cannot be converted, does not match.
+// A nil value is an empty cell: it is zero, and equal only to "".
+//
+// An invalid expression is returned by Err and by the Flush methods, and
+// nothing is written.
+func WithFilter(expr string) func(*Table) {
+	return func(t *Table) {
+		f, err := parseFilter(expr)
+		if err != nil {
+			t.err = fmt.Errorf("%w %q: %w", ErrFilter, expr, err)
+
+			return
+		}
+
+		t.filter = f
+	}
+}
+
+// Err returns the first error from applying the options, such as an invalid
+// WithFilter expression.
+func (t *Table) Err() error {
+	return t.err
+}
+
+// filter is a node of a parsed WithFilter expression.
+type filter interface {
+	match(info []Column, row []Cell) bool
+}
+
+type (
+	andFilter struct{ left, right filter }
+	orFilter  struct{ left, right filter }
+	notFilter struct{ filter filter }
+
+	// compareFilter compares a column with a value.
+	compareFilter struct {
+		column string
+		op     string // empty for a column alone
+		value  string
+		re     *regexp.Regexp // for =~ and !~
+	}
+)
+
+func (f andFilter) match(info []Column, row []Cell) bool {
+	return f.left.match(info, row) && f.right.match(info, row)
+}
+
+func (f orFilter) match(info []Column, row []Cell) bool {
+	return f.left.match(info, row) || f.right.match(info, row)
+}
+
+func (f notFilter) match(info []Column, row []Cell) bool {
+	return !f.filter.match(info, row)
+}
+
+func (f compareFilter) match(info []Column, row []Cell) bool {
+	j := slices.IndexFunc(info, func(c Column) bool { return c.matches(f.column) })
+	if j < 0 {
+		return false
+	}
+
+	cell := row[j]
+
+	switch {
+	case f.op == "=~":
+		return f.re.MatchString(cell.Text)
+	case f.op == "!~":
+		return !f.re.MatchString(cell.Text)
+	case f.op == "":
+		return cell.Value.IsValid() && !cell.Value.IsZero()
+	}
+
+	var n int
+
+	if cell.Value.IsValid() {
+		a, b := cell.Value, filterValue(cell.Value.Type(), f.value)
+		if !b.IsValid() {
+			return false
+		}
+
+		if b.Kind() == reflect.String && a.Kind() != reflect.String { // compare the text of other types
+			a = reflect.ValueOf(cell.Text)
+		}
+
+		n = compareValues(a, b)
+	} else { // nil pointers and interfaces are empty, and sort first
+		n = strings.Compare("", f.value)
+	}
+
+	switch f.op {
+	case "==":
+		return n == 0
+	case "!=":
+		return n != 0
+	case "<":
+		return n < 0
+	case "<=":
+		return n <= 0
+	case ">":
+		return n > 0
+	default: // >=
+		return n >= 0
+	}
+}
+
+// filterValue converts s to a value that compares with values of type typ, or
+// returns the zero Value if it cannot.
+func filterValue(typ reflect.Type, s string) reflect.Value {
+	var (
+		v   any
+		err error
+	)
+
+	switch {
+	case typ == reflect.TypeFor[time.Time]():
+		if v, err = time.Parse(time.RFC3339, s); err != nil {
+			v, err = time.Parse(time.DateOnly, s)
+		}
+	case typ == reflect.TypeFor[time.Duration]():
+		v, err = time.ParseDuration(s)
+	case typ.Kind() == reflect.Bool:
+		var b bool
+
+		b, err = strconv.ParseBool(s)
+		v = strconv.FormatBool(b) // booleans compare by text
+	default:
+		switch {
+		case reflect.Zero(typ).CanInt():
+			v, err = strconv.ParseInt(s, 0, 64)
+		case reflect.Zero(typ).CanUint():
+			v, err = strconv.ParseUint(s, 0, 64)
+		case reflect.Zero(typ).CanFloat():
+			v, err = strconv.ParseFloat(s, 64)
+		default:
+			v = s
+		}
+	}
+
+	if err != nil {
+		return reflect.Value{}
+	}
+
+	return reflect.ValueOf(v)
+}
+
+// filterParser is a recursive descent parser for WithFilter expressions:
+//
+//	or      = and { "||" and }
+//	and     = unary { "&&" unary }
+//	unary   = "!" unary | "(" or ")" | compare
+//	compare = word [ op ( word | string ) ]
+type filterParser struct {
+	tokens []filterToken
+}
+
+type filterToken struct {
+	text   string
+	quoted bool
+}
+
+var filterOperators = []string{"&&", "||", "==", "!=", "<=", ">=", "=~", "!~", "<", ">", "!", "(", ")"}
+
+func parseFilter(expr string) (filter, error) {
+	tokens, err := scanFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := filterParser{tokens: tokens}
+
+	f, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(p.tokens) > 0 {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[0].text)
+	}
+
+	return f, nil
+}
+
+// scanFilter splits expr into operators, quoted strings and words.
+func scanFilter(expr string) ([]filterToken, error) {
+	var tokens []filterToken
+
+	for s := strings.TrimSpace(expr); s != ""; s = strings.TrimLeftFunc(s, unicode.IsSpace) {
+		if s[0] == '"' || s[0] == '`' {
+			quoted, err := strconv.QuotedPrefix(s)
+			if err != nil {
+				return nil, fmt.Errorf("unterminated string %s", s)
+			}
+
+			text, _ := strconv.Unquote(quoted)
+			tokens = append(tokens, filterToken{text: text, quoted: true})
+			s = s[len(quoted):]
+
+			continue
+		}
+
+		if op := operatorPrefix(s); op != "" {
+			tokens = append(tokens, filterToken{text: op})
+			s = s[len(op):]
+
+			continue
+		}
+
+		end := strings.IndexFunc(s, func(r rune) bool {
+			return unicode.IsSpace(r) || strings.ContainsRune("&|=!<>~()\"`", r)
+		})
+		if end < 0 {
+			end = len(s)
+		}
+
+		if end == 0 {
+			return nil, fmt.Errorf("unexpected %q", s[:1])
+		}
+
+		tokens = append(tokens, filterToken{text: s[:end]})
+		s = s[end:]
+	}
+
+	return tokens, nil
+}
+
+func operatorPrefix(s string) string {
+	for _, op := range filterOperators {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+
+	return ""
+}
+
+// next removes and returns the next token if it is one of the operators.
+func (p *filterParser) next(ops ...string) (string, bool) {
+	if len(p.tokens) == 0 || p.tokens[0].quoted || !slices.Contains(ops, p.tokens[0].text) {
+		return "", false
+	}
+
+	op := p.tokens[0].text
+	p.tokens = p.tokens[1:]
+
+	return op, true
+}
+
+func (p *filterParser) or() (filter, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, ok := p.next("||"); !ok {
+			return left, nil
+		}
+
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+
+		left = orFilter{left: left, right: right}
+	}
+}
+
+func (p *filterParser) and() (filter, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, ok := p.next("&&"); !ok {
+			return left, nil
+		}
+
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = andFilter{left: left, right: right}
+	}
+}
+
+func (p *filterParser) unary() (filter, error) {
+	if _, ok := p.next("!"); ok {
+		f, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		return notFilter{filter: f}, nil
+	}
+
+	if _, ok := p.next("("); ok {
+		f, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := p.next(")"); !ok {
+			return nil, errors.New(`missing ")"`)
+		}
+
+		return f, nil
+	}
+
+	return p.compare()
+}
+
+func (p *filterParser) compare() (filter, error) {
+	column, ok := p.word(false)
+	if !ok {
+		return nil, errors.New("expected a column")
+	}
+
+	op, ok := p.next("==", "!=", "<", "<=", ">", ">=", "=~", "!~")
+	if !ok {
+		return compareFilter{column: column}, nil
+	}
+
+	value, ok := p.word(true)
+	if !ok {
+		return nil, fmt.Errorf("expected a value after %q", column+" "+op)
+	}
+
+	f := compareFilter{column: column, op: op, value: value}
+
+	if op == "=~" || op == "!~" {
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return nil, err
+		}
+
+		f.re = re
+	}
+
+	return f, nil
+}
+
+// word removes and returns the next token if it is a word, or a quoted string
+// if quoted is true.
+func (p *filterParser) word(quoted bool) (string, bool) {
+	if len(p.tokens) == 0 {
+		return "", false
+	}
+
+	tok := p.tokens[0]
+	if tok.quoted && !quoted || !tok.quoted && operatorPrefix(tok.text) != "" {
+		return "", false
+	}
+
+	p.tokens = p.tokens[1:]
+
+	return tok.text, true
+}
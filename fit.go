@@ -0,0 +1,282 @@
+package table
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rivo/uniseg"
+	"golang.org/x/term"
+)
+
+// overflow is the policy for a column whose text is wider than the space left
+// for it when fitting the table to the terminal.
+type overflow int
+
+const (
+	overflowNone overflow = iota // never shrink the column
+	overflowTruncateEnd
+	overflowTruncateStart
+	overflowTruncateMiddle
+	overflowWrap
+)
+
+const ellipsis = "…"
+
+// WithMaxWidth is an option setting function for New. It sets the maximum
+// width of the text output. The default is the width of the terminal, or
+// unlimited if the io.Writer is not a terminal.
+//
+// Tables wider than the maximum are fit by shrinking the columns that have an
+// overflow policy in their "table" struct tag:
+//
+//	truncate        truncate the end of the text with an ellipsis
+//	truncate=start  truncate the start of the text
+//	truncate=middle truncate the middle of the text, useful for paths
+//	wrap            word wrap the text into multiple lines
+//	noshrink        never shrink the column (the default)
+//
+// If the table is still too wide, columns with a "priority=N" option are
+// dropped, highest N first. Columns without a priority are never dropped.
+func WithMaxWidth(n int) func(*Table) {
+	return func(t *Table) {
+		t.maxWidth = n
+	}
+}
+
+func parseOverflow(opts tagOptions) overflow {
+	switch {
+	case opts.has("wrap"):
+		return overflowWrap
+	case !opts.has("truncate"):
+		return overflowNone
+	}
+
+	switch opts["truncate"] {
+	case "start":
+		return overflowTruncateStart
+	case "middle":
+		return overflowTruncateMiddle
+	default:
+		return overflowTruncateEnd
+	}
+}
+
+// width returns the maximum width of the text output to w, or 0 if it is
+// unlimited.
+func (t *Table) width(w io.Writer) int {
+	if t.maxWidth > 0 {
+		return t.maxWidth
+	}
+
+	f, ok := w.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return 0
+	}
+
+	width, _, err := term.GetSize(int(f.Fd()))
+	if err != nil {
+		return 0
+	}
+
+	return width
+}
+
+// fit shrinks and drops columns until the table is no wider than width. The
+// columns are separated by separator cells.
+func fit(info []Column, width, separator int) {
+	if width <= 0 {
+		return
+	}
+
+	for tableWidth(info, true, separator) > width {
+		j := dropCandidate(info)
+		if j < 0 {
+			break
+		}
+
+		info[j].dropped = true
+	}
+
+	for excess := tableWidth(info, false, separator) - width; excess > 0; excess-- {
+		j := -1
+
+		for k := range info {
+			if info[k].visible() && info[k].Width > info[k].minWidth() && (j < 0 || info[k].Width > info[j].Width) {
+				j = k
+			}
+		}
+
+		if j < 0 {
+			break
+		}
+
+		info[j].Width--
+	}
+}
+
+// tableWidth returns the width of the visible columns and the separators
+// between them. If shrunk is true the columns that can shrink are measured at
+// their minimum width.
+func tableWidth(info []Column, shrunk bool, separator int) int {
+	var width, n int
+
+	for _, c := range info {
+		if !c.visible() {
+			continue
+		}
+
+		if shrunk {
+			width += c.minWidth()
+		} else {
+			width += c.Width
+		}
+
+		n++
+	}
+
+	return width + max(n-1, 0)*separator
+}
+
+// dropCandidate returns the index of the visible column with the highest
+// priority, or -1 if no column can be dropped.
+func dropCandidate(info []Column) int {
+	j := -1
+
+	for k := range info {
+		if info[k].visible() && info[k].priority > 0 && (j < 0 || info[k].priority >= info[j].priority) {
+			j = k
+		}
+	}
+
+	return j
+}
+
+// minWidth returns the narrowest the column can be shrunk to. The header
+// labels are never shrunk.
+func (c Column) minWidth() int {
+	if c.overflow == overflowNone {
+		return c.Width
+	}
+
+	return min(c.Width, max(maxStringLength(c.Labels), 1))
+}
+
+// visible returns true if the column is printed.
+func (c Column) visible() bool {
+	return !c.IsZero && !c.dropped && !c.grouped
+}
+
+// truncate shortens s to width, replacing the removed grapheme clusters with an
+// ellipsis.
+func truncate(s string, width int, o overflow) string {
+	if displayWidth(s) <= width {
+		return s
+	}
+
+	if width < 1 {
+		return ""
+	}
+
+	width -= displayWidth(ellipsis)
+
+	switch o {
+	case overflowTruncateStart:
+		return ellipsis + tail(s, width)
+	case overflowTruncateMiddle:
+		return head(s, width-width/2) + ellipsis + tail(s, width/2)
+	default:
+		return head(s, width) + ellipsis
+	}
+}
+
+// head returns the leading grapheme clusters of s that fit in width.
+func head(s string, width int) string {
+	var (
+		b     strings.Builder
+		state = -1
+		used  int
+	)
+
+	for s != "" {
+		var (
+			cluster string
+			w       int
+		)
+
+		cluster, s, w, state = uniseg.FirstGraphemeClusterInString(s, state)
+		if used+w > width {
+			break
+		}
+
+		b.WriteString(cluster)
+		used += w
+	}
+
+	return b.String()
+}
+
+// tail returns the trailing grapheme clusters of s that fit in width.
+func tail(s string, width int) string {
+	var clusters []string
+
+	g := uniseg.NewGraphemes(s)
+	for g.Next() {
+		clusters = append(clusters, g.Str())
+	}
+
+	used, i := 0, len(clusters)
+
+	for i > 0 {
+		w := displayWidth(clusters[i-1])
+		if used+w > width {
+			break
+		}
+
+		used += w
+		i--
+	}
+
+	return strings.Join(clusters[i:], "")
+}
+
+// wrapText word wraps s into lines no wider than width. Words that are wider
+// than width are broken at grapheme cluster boundaries.
+func wrapText(s string, width int) []string {
+	var lines []string
+
+	for paragraph := range strings.SplitSeq(s, "\n") {
+		var line string
+
+		for _, word := range strings.Fields(paragraph) {
+			for displayWidth(word) > width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+
+				h := head(word, width)
+				if h == "" { // width is narrower than a single cluster
+					break
+				}
+
+				lines = append(lines, h)
+				word = word[len(h):]
+			}
+
+			switch {
+			case line == "":
+				line = word
+			case displayWidth(line)+1+displayWidth(word) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
@@ -0,0 +1,199 @@
+package table
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrUnknownFormat is returned when a Format has not been registered.
+var ErrUnknownFormat = errors.New("unknown format")
+
+// Format is the name of a registered output format.
+type Format string
+
+// Built-in output formats.
+const (
+	FormatText     Format = "text"
+	FormatJSON     Format = "json"
+	FormatYAML     Format = "yaml"
+	FormatCSV      Format = "csv"
+	FormatTSV      Format = "tsv"
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+)
+
+// Encoder writes the sections of a Table in an output format.
+type Encoder interface {
+	Encode(w io.Writer, sections []Section) error
+}
+
+// EncoderFunc is an adapter to allow the use of ordinary functions as
+// Encoders.
+type EncoderFunc func(w io.Writer, sections []Section) error
+
+// Section is a run of consecutive rows that share a struct type. Each section
+// is rendered as its own table.
+type Section struct {
+	Type        reflect.Type
+	Columns     []Column
+	Rows        []any        // Rows are the structs as written to the Table.
+	Cells       [][]Cell     // Cells holds a row of Cells for each of the Rows.
+	Annotations []Annotation // Annotations are indexed relative to the section.
+	Footer      []Cell       // Footer is nil unless a column has an Aggregate.
+	Groups      []Group      // Groups is nil unless WithGroupBy is set.
+}
+
+// Column describes a struct field rendered as a table column.
+type Column struct {
+	Name        string       // Name is the struct field name.
+	Labels      []string     // Labels are the header lines.
+	Kind        reflect.Kind // Kind is the kind of the struct field.
+	Width       int          // Width is the length of the longest label or cell.
+	OmitEmpty   bool         // OmitEmpty is set from the "table" struct tag.
+	IsZero      bool         // IsZero is true if the column is omitted because it is empty.
+	Align       Alignment    // Align is set from the "table" struct tag or WithNumericAlignment.
+	Aggregate   Aggregate    // Aggregate is set from the "table" struct tag or WithFooter.
+	exported    bool
+	index       []int // index of the struct field for reflect.Value.FieldByIndex
+	intWidth    int   // width of the integer part of AlignDecimal cells
+	fracWidth   int   // width of the fractional part of AlignDecimal cells
+	overflow    overflow
+	priority    int    // columns with the highest priority are dropped first
+	dropped     bool   // dropped to fit the table to the maximum width
+	grouped     bool   // grouped columns are shown in the group headers
+	sort        int    // sort priority, negative for descending order
+	tier        string // tier columns are hidden unless shown by WithTier
+	separator   string // separator of list elements
+	limit       int    // maximum number of list elements shown
+	explode     bool   // list elements are shown one per line
+	layout      string // time.Time layout, or "relative"
+	location    *time.Location
+	round       time.Duration // time.Duration rounding
+	formatter   func(reflect.Type) typeFormatter
+	units       *units // human readable units
+	scaleColumn bool   // the units are the same for the whole column
+	unit        string // the unit of the whole column, shown in the header
+	locale      *locale
+	precision   int // decimal places of floats, or -1
+	styles      []styleRule
+}
+
+// Cell is a single struct field value.
+type Cell struct {
+	Text  string        // Text is the uncolored formatted value.
+	Value reflect.Value // Value is the field value, invalid if it is nil.
+}
+
+// wrapper returns the value of c if it implements wrapper.
+func (c Cell) wrapper() (wrapper, bool) {
+	if !c.Value.IsValid() || !c.Value.CanInterface() {
+		return nil, false
+	}
+
+	a, ok := c.Value.Interface().(wrapper)
+
+	return a, ok
+}
+
+var (
+	formatsMu sync.RWMutex
+	formats   = map[Format]func(*Table) Encoder{
+		FormatText:     func(t *Table) Encoder { return EncoderFunc(t.encodeText) },
+		FormatJSON:     func(t *Table) Encoder { return EncoderFunc(t.encodeJSON) },
+		FormatYAML:     func(t *Table) Encoder { return EncoderFunc(t.encodeYAML) },
+		FormatCSV:      func(t *Table) Encoder { return EncoderFunc(t.encodeCSV) },
+		FormatTSV:      func(t *Table) Encoder { return EncoderFunc(t.encodeTSV) },
+		FormatMarkdown: func(t *Table) Encoder { return EncoderFunc(t.encodeMarkdown) },
+		FormatHTML:     func(t *Table) Encoder { return EncoderFunc(t.encodeHTML) },
+	}
+)
+
+// Encode calls f(w, sections).
+func (f EncoderFunc) Encode(w io.Writer, sections []Section) error {
+	return f(w, sections)
+}
+
+// RegisterFormat makes an output format available by name to ParseFormat and
+// AsFormat. The factory is called by Flush to create an Encoder for the Table
+// being flushed. Names are case insensitive. If RegisterFormat is called twice
+// with the same name or if factory is nil, it panics.
+func RegisterFormat(name string, factory func(*Table) Encoder) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+
+	if factory == nil {
+		panic("table: RegisterFormat factory is nil")
+	}
+
+	f := Format(strings.ToLower(name))
+	if _, dup := formats[f]; dup {
+		panic("table: RegisterFormat called twice for format " + name)
+	}
+
+	formats[f] = factory
+}
+
+// ParseFormat returns the registered Format matching the case insensitive
+// name s. This is intended for mapping command line flags such as "--output"
+// to a Format.
+func ParseFormat(s string) (Format, error) {
+	f := Format(strings.ToLower(strings.TrimSpace(s)))
+
+	if _, err := lookupFormat(f); err != nil {
+		return "", err
+	}
+
+	return f, nil
+}
+
+// Formats returns the sorted names of all registered formats.
+func Formats() []string {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+
+	names := make([]string, 0, len(formats))
+	for f := range formats {
+		names = append(names, string(f))
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// AsFormat is an option setting function for New. It sets f as the default
+// output format for Flush.
+func AsFormat(f Format) func(*Table) {
+	return func(t *Table) {
+		t.format = f
+	}
+}
+
+func lookupFormat(f Format) (func(*Table) Encoder, error) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+
+	factory, ok := formats[f]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, f)
+	}
+
+	return factory, nil
+}
+
+// sectionRows returns the structs of all the sections.
+func sectionRows(sections []Section) []any {
+	var r []any
+
+	for _, s := range sections {
+		r = append(r, s.Rows...)
+	}
+
+	return r
+}
@@ -0,0 +1,74 @@
+package table
+
+import (
+	"reflect"
+	"sync"
+)
+
+// typeFormatter formats a value of a registered type.
+type typeFormatter func(reflect.Value) string
+
+var (
+	typeFormattersMu sync.RWMutex
+	typeFormatters   = map[reflect.Type]typeFormatter{}
+)
+
+// RegisterFormatter sets the function used to format the cells of every Table
+// with values of type T, such as netip.Addr or a UUID type. Fields that point
+// to a T, and the elements of slices and maps of T, use it too. A later call
+// for the same type replaces the function. RegisterFormatter panics if fn is
+// nil.
+func RegisterFormatter[T any](fn func(T) string) {
+	if fn == nil {
+		panic("table: RegisterFormatter function is nil")
+	}
+
+	typeFormattersMu.Lock()
+	defer typeFormattersMu.Unlock()
+
+	typeFormatters[reflect.TypeFor[T]()] = newTypeFormatter(fn)
+}
+
+// WithFormatter is an option setting function for New. It sets the function
+// used to format the cells with values of type T, like RegisterFormatter but
+// only for this Table. It takes precedence over RegisterFormatter.
+func WithFormatter[T any](fn func(T) string) func(*Table) {
+	return func(t *Table) {
+		if t.formatters == nil {
+			t.formatters = map[reflect.Type]typeFormatter{}
+		}
+
+		t.formatters[reflect.TypeFor[T]()] = newTypeFormatter(fn)
+	}
+}
+
+// WithFormattedValues is an option setting function for New. It makes the JSON
+// and YAML output encode the values with a formatter from WithFormatter or
+// RegisterFormatter as their text. It implies WithColumnEncoding.
+func WithFormattedValues() func(*Table) {
+	return func(t *Table) {
+		t.byColumn = true
+		t.formattedValues = true
+	}
+}
+
+func newTypeFormatter[T any](fn func(T) string) typeFormatter {
+	return func(v reflect.Value) string {
+		a, _ := v.Interface().(T) // only called for values of type T
+
+		return fn(a)
+	}
+}
+
+// formatter returns the formatter for values of type typ, or nil if it has
+// none.
+func (t *Table) formatter(typ reflect.Type) typeFormatter {
+	if fn, ok := t.formatters[typ]; ok {
+		return fn
+	}
+
+	typeFormattersMu.RLock()
+	defer typeFormattersMu.RUnlock()
+
+	return typeFormatters[typ]
+}
@@ -4,6 +4,7 @@ go 1.24.0
 
 require (
 	github.com/goccy/go-yaml v1.19.2
+	github.com/rivo/uniseg v0.4.7
 	golang.org/x/term v0.40.0
 )
 
@@ -0,0 +1,141 @@
+package table
+
+import (
+	"slices"
+	"strings"
+)
+
+// Group is a run of rows in a Section with the same values in the columns of
+// WithGroupBy.
+type Group struct {
+	Start  int      // Start is the index of the first row of the group.
+	End    int      // End is the index after the last row of the group.
+	Labels []string // Labels are the "LABEL: value" pairs of the grouped columns.
+	Footer []Cell   // Footer is the subtotals, nil unless a column has an Aggregate.
+}
+
+// subtotalLabel is printed in the first cell of a group footer if its column
+// has no Aggregate.
+const subtotalLabel = "SUBTOTAL"
+
+// WithGroupBy is an option setting function for New. It buckets the rows of
+// the text output by the values of the columns with the given header labels
+// or struct field names. Each group starts with a line showing its values, the
+// grouped columns are not printed, and if any column has a footer Aggregate
+// each group ends with subtotals.
+//
+// Groups are nested in the order of the columns, and keep the order in which
+// their values first appear. The other output formats only get the rows in
+// group order, without the group lines and subtotals, and show the grouped
+// columns.
+func WithGroupBy(columns ...string) func(*Table) {
+	return func(t *Table) {
+		t.groupBy = columns
+	}
+}
+
+// group buckets the rows of s by the WithGroupBy columns.
+func (t *Table) group(s *Section) {
+	var by []int
+
+	for _, name := range t.groupBy {
+		for j := range s.Columns {
+			if s.Columns[j].matches(name) {
+				s.Columns[j].grouped = true
+				by = append(by, j)
+			}
+		}
+	}
+
+	if len(by) == 0 {
+		return
+	}
+
+	idx := make([]int, len(s.Cells))
+	for i := range idx {
+		idx[i] = i
+	}
+
+	reorder(s, bucket(s.Cells, idx, by))
+
+	for i := range s.Cells {
+		if i > 0 && sameGroup(s.Cells[i-1], s.Cells[i], by) {
+			s.Groups[len(s.Groups)-1].End++
+
+			continue
+		}
+
+		labels := make([]string, len(by))
+		for k, j := range by {
+			labels[k] = strings.Join(s.Columns[j].Labels, " ") + ": " + s.Cells[i][j].Text
+		}
+
+		s.Groups = append(s.Groups, Group{Start: i, End: i + 1, Labels: labels})
+	}
+}
+
+// sameGroup returns true if the rows a and b have the same text in the columns
+// by.
+func sameGroup(a, b []Cell, by []int) bool {
+	for _, j := range by {
+		if a[j].Text != b[j].Text {
+			return false
+		}
+	}
+
+	return true
+}
+
+// bucket returns the row indexes idx grouped by the text of the columns by.
+func bucket(rows [][]Cell, idx []int, by []int) []int {
+	if len(by) == 0 {
+		return idx
+	}
+
+	var (
+		keys    []string
+		buckets = map[string][]int{}
+	)
+
+	for _, i := range idx {
+		key := rows[i][by[0]].Text
+		if _, ok := buckets[key]; !ok {
+			keys = append(keys, key)
+		}
+
+		buckets[key] = append(buckets[key], i)
+	}
+
+	sorted := make([]int, 0, len(idx))
+	for _, key := range keys {
+		sorted = append(sorted, bucket(rows, buckets[key], by[1:])...)
+	}
+
+	return sorted
+}
+
+// reorder rearranges the rows of s so the new row i is the old row order[i].
+// Annotations move with the row they precede.
+func reorder(s *Section, order []int) {
+	rows := make([]any, len(order))
+	cells := make([][]Cell, len(order))
+	moved := make([]int, len(order))
+
+	for i, j := range order {
+		rows[i] = s.Rows[j]
+		cells[i] = s.Cells[j]
+		moved[j] = i
+	}
+
+	s.Rows, s.Cells = rows, cells
+
+	for i := range s.Annotations {
+		if a := &s.Annotations[i]; a.Index < len(moved) {
+			a.Index = moved[a.Index]
+		}
+	}
+
+	slices.SortStableFunc(s.Annotations, func(a, b Annotation) int {
+		return a.Index - b.Index
+	})
+}
@@ -0,0 +1,121 @@
+package table
+
+import (
+	"html"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// AsHTML is an option setting function for New. It sets HTML as the default
+// output format for Flush.
+func AsHTML() func(*Table) {
+	return func(t *Table) {
+		t.format = FormatHTML
+	}
+}
+
+// FlushHTML flushes the Table data to its io.Writer as HTML tables. Each
+// struct type starts a new <table>. Rather than ANSI styles, the elements are
+// given CSS classes named after the Colors roles: "header", "even-row",
+// "odd-row", "empty", "repeat", "annotation" and "footer".
+func (t *Table) FlushHTML() error {
+	if t.err != nil {
+		return t.err
+	}
+
+	return t.encodeHTML(t.writer, t.sections())
+}
+
+func (*Table) encodeHTML(w io.Writer, sections []Section) error {
+	var b strings.Builder
+
+	for _, s := range sections {
+		var numColumns int
+
+		b.WriteString("<table>\n<thead>\n<tr class=\"header\">")
+
+		for _, c := range s.Columns {
+			if c.IsZero {
+				continue
+			}
+
+			labels := make([]string, len(c.Labels))
+			for i := range c.Labels {
+				labels[i] = html.EscapeString(c.Labels[i])
+			}
+
+			b.WriteString("<th>" + strings.Join(labels, "<br>") + "</th>")
+
+			numColumns++
+		}
+
+		b.WriteString("</tr>\n</thead>\n<tbody>\n")
+
+		annotation := func(text string) {
+			b.WriteString("<tr class=\"annotation\"><td colspan=\"" + strconv.Itoa(numColumns) + "\">" +
+				html.EscapeString(text) + "</td></tr>\n")
+		}
+
+		annotations := s.Annotations
+
+		for i := range s.Cells {
+			for len(annotations) > 0 && annotations[0].Index == i {
+				annotation(annotations[0].Text)
+				annotations = annotations[1:]
+			}
+
+			repeats := make([]bool, len(s.Cells[i]))
+			if i > 0 {
+				repeats = findRepeats(s.Cells[i-1], s.Cells[i])
+			}
+
+			if i%2 != 0 {
+				b.WriteString("<tr class=\"odd-row\">")
+			} else {
+				b.WriteString("<tr class=\"even-row\">")
+			}
+
+			for j, cell := range s.Cells[i] {
+				if s.Columns[j].IsZero {
+					continue
+				}
+
+				switch {
+				case cell.Text == "":
+					b.WriteString("<td class=\"empty\"></td>")
+				case repeats[j]:
+					b.WriteString("<td class=\"repeat\">" + html.EscapeString(cell.Text) + "</td>")
+				default:
+					b.WriteString("<td>" + html.EscapeString(cell.Text) + "</td>")
+				}
+			}
+
+			b.WriteString("</tr>\n")
+		}
+
+		for _, a := range annotations {
+			annotation(a.Text)
+		}
+
+		b.WriteString("</tbody>\n")
+
+		if s.Footer != nil {
+			b.WriteString("<tfoot>\n<tr class=\"footer\">")
+
+			for j, cell := range ungroupedFooter(s.Columns, s.Footer) {
+				if !s.Columns[j].IsZero {
+					b.WriteString("<td>" + html.EscapeString(cell.Text) + "</td>")
+				}
+			}
+
+			b.WriteString("</tr>\n</tfoot>\n")
+		}
+
+		b.WriteString("</table>\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
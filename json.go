@@ -1,12 +1,15 @@
 package table
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"io"
+)
 
 // AsJSON is an option setting function for New. It sets JSON as the default
 // output format for Flush.
 func AsJSON() func(*Table) {
 	return func(t *Table) {
-		t.style = jsonOutput
+		t.format = FormatJSON
 	}
 }
 
@@ -21,8 +24,16 @@ func NewJSON(opts ...func(*Table)) *Table {
 
 // FlushJSON flushes the Table data to its io.Writer as JSON.
 func (t *Table) FlushJSON() error {
-	e := json.NewEncoder(t.writer)
+	if t.err != nil {
+		return t.err
+	}
+
+	return t.encodeJSON(t.writer, t.sections())
+}
+
+func (t *Table) encodeJSON(w io.Writer, sections []Section) error {
+	e := json.NewEncoder(w)
 	e.SetIndent("", "    ")
 
-	return e.Encode(t.rows)
+	return e.Encode(t.encodable(sections))
 }
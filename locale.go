@@ -0,0 +1,129 @@
+package table
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownLocale is returned from Flush if the tag given to WithLocale is not
+// supported.
+var ErrUnknownLocale = errors.New("unknown locale")
+
+// locale is the digit grouping separator and decimal mark of a language.
+type locale struct {
+	group   string
+	decimal string
+}
+
+var (
+	pointLocale      = locale{group: ",", decimal: "."}
+	commaLocale      = locale{group: ".", decimal: ","}
+	spaceLocale      = locale{group: "\u00a0", decimal: ","} // no-break space
+	apostropheLocale = locale{group: "’", decimal: "."}
+)
+
+// locales are the supported languages, and regions that differ from their
+// language.
+var locales = map[string]*locale{
+	"en": &pointLocale, "ja": &pointLocale, "ko": &pointLocale, "zh": &pointLocale,
+	"he": &pointLocale, "th": &pointLocale, "es-mx": &pointLocale, "es-us": &pointLocale,
+
+	"de": &commaLocale, "es": &commaLocale, "it": &commaLocale, "nl": &commaLocale,
+	"pt": &commaLocale, "da": &commaLocale, "id": &commaLocale, "tr": &commaLocale,
+	"el": &commaLocale, "ro": &commaLocale, "hr": &commaLocale, "sl": &commaLocale,
+
+	"fr": &spaceLocale, "ru": &spaceLocale, "pl": &spaceLocale, "cs": &spaceLocale,
+	"sk": &spaceLocale, "sv": &spaceLocale, "nb": &spaceLocale, "no": &spaceLocale,
+	"fi": &spaceLocale, "uk": &spaceLocale, "hu": &spaceLocale, "bg": &spaceLocale,
+
+	"de-ch": &apostropheLocale, "fr-ch": &apostropheLocale, "it-ch": &apostropheLocale,
+}
+
+// WithLocale is an option setting function for New. It formats the numbers of
+// the text output with the digit grouping separator and decimal mark of the
+// language of a BCP 47 tag, such as "en-US" (1,234,567.8) or "de" (1.234.567,8).
+// JSON and YAML keep the numbers. An unsupported tag is returned by Err and by
+// the Flush methods.
+func WithLocale(tag string) func(*Table) {
+	return func(t *Table) {
+		lang, region, _ := strings.Cut(strings.ToLower(strings.ReplaceAll(tag, "_", "-")), "-")
+
+		l, ok := locales[lang+"-"+region]
+		if !ok {
+			l, ok = locales[lang]
+		}
+
+		if !ok {
+			t.err = fmt.Errorf("%w: %q", ErrUnknownLocale, tag)
+
+			return
+		}
+
+		t.locale = l
+	}
+}
+
+// number localizes a number formatted by strconv.
+func (l *locale) number(s string) string {
+	if l == nil {
+		return s
+	}
+
+	var sign string
+
+	if rest, ok := strings.CutPrefix(s, "-"); ok {
+		sign, s = "-", rest
+	}
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if strings.Trim(whole, "0123456789") != "" { // NaN and Inf
+		return sign + s
+	}
+
+	var b strings.Builder
+
+	b.WriteString(sign)
+
+	for i := range len(whole) {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(l.group)
+		}
+
+		b.WriteByte(whole[i])
+	}
+
+	if frac != "" {
+		b.WriteString(l.decimal + frac)
+	}
+
+	return b.String()
+}
+
+// formatNumber formats the integer or floating point number v with the locale
+// and the "prec" tag option of the column.
+func (c Column) formatNumber(v reflect.Value) (string, bool) {
+	switch {
+	case v.CanInt():
+		return c.locale.number(strconv.FormatInt(v.Int(), 10)), true
+	case v.CanUint():
+		return c.locale.number(strconv.FormatUint(v.Uint(), 10)), true
+	case v.CanFloat() && c.precision >= 0:
+		return c.locale.number(strconv.FormatFloat(v.Float(), 'f', c.precision, v.Type().Bits())), true
+	case v.CanFloat():
+		return c.locale.number(formatFloat(v.Float(), v.Type().Bits())), true
+	default:
+		return "", false
+	}
+}
+
+// decimalMark returns the decimal mark of the column's locale.
+func (c Column) decimalMark() string {
+	if c.locale == nil {
+		return "."
+	}
+
+	return c.locale.decimal
+}
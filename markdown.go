@@ -0,0 +1,161 @@
+package table
+
+import (
+	"io"
+	"slices"
+	"strings"
+)
+
+var markdownEscaper = strings.NewReplacer(
+	`|`, `\|`,
+	"\r\n", "<br>",
+	"\n", "<br>",
+)
+
+// AsMarkdown is an option setting function for New. It sets GitHub flavored
+// Markdown as the default output format for Flush.
+func AsMarkdown() func(*Table) {
+	return func(t *Table) {
+		t.format = FormatMarkdown
+	}
+}
+
+// FlushMarkdown flushes the Table data to its io.Writer as GitHub flavored
+// Markdown pipe tables. Columns use the alignment from their "table" struct tag,
+// with numeric columns right aligned by default. Annotations are written as
+// paragraphs that split the table into segments.
+func (t *Table) FlushMarkdown() error {
+	if t.err != nil {
+		return t.err
+	}
+
+	return t.encodeMarkdown(t.writer, t.sections())
+}
+
+func (*Table) encodeMarkdown(w io.Writer, sections []Section) error {
+	var blocks []string
+
+	for _, s := range sections {
+		cells := s.Cells
+		if s.Footer != nil { // the footer is the last row of the last segment
+			cells = append(slices.Clip(cells), ungroupedFooter(s.Columns, s.Footer))
+		}
+
+		var (
+			header  []string
+			columns []Column
+			rows    = make([][]string, len(cells))
+		)
+
+		for j, c := range s.Columns {
+			if c.IsZero {
+				continue
+			}
+
+			columns = append(columns, c)
+			header = append(header, markdownEscaper.Replace(strings.Join(c.Labels, "\n")))
+
+			for i := range cells {
+				rows[i] = append(rows[i], markdownEscaper.Replace(cells[i][j].Text))
+			}
+		}
+
+		widths := make([]int, len(header))
+
+		for j := range header {
+			widths[j] = max(3, displayWidth(header[j])) // "---" is the minimum separator
+
+			for i := range rows {
+				widths[j] = max(widths[j], displayWidth(rows[i][j]))
+			}
+		}
+
+		annotations := s.Annotations
+		start := 0
+
+		for i := 0; i <= len(s.Cells); i++ {
+			if len(annotations) == 0 || annotations[0].Index != i {
+				continue
+			}
+
+			if i > start {
+				end := i
+				if i == len(s.Cells) {
+					end = len(rows)
+				}
+
+				blocks = append(blocks, markdownTable(columns, widths, header, rows[start:end]))
+				start = end
+			}
+
+			for len(annotations) > 0 && annotations[0].Index == i {
+				blocks = append(blocks, annotations[0].Text)
+				annotations = annotations[1:]
+			}
+		}
+
+		if start < len(rows) {
+			blocks = append(blocks, markdownTable(columns, widths, header, rows[start:]))
+		}
+	}
+
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	_, err := io.WriteString(w, strings.Join(blocks, "\n\n")+"\n")
+
+	return err
+}
+
+func markdownTable(columns []Column, widths []int, header []string, rows [][]string) string {
+	var b strings.Builder
+
+	writeRow := func(cells []string) {
+		for j := range cells {
+			left, right := pad(markdownAlignment(columns[j]), widths[j]-displayWidth(cells[j]))
+
+			b.WriteString("| " + left + cells[j] + right + " ")
+		}
+
+		b.WriteString("|\n")
+	}
+
+	writeRow(header)
+
+	for j := range columns {
+		b.WriteString("| ")
+
+		switch markdownAlignment(columns[j]) {
+		case AlignRight:
+			b.WriteString(strings.Repeat("-", widths[j]-1) + ":")
+		case AlignCenter:
+			b.WriteString(":" + strings.Repeat("-", widths[j]-2) + ":")
+		case AlignLeft:
+			b.WriteString(":" + strings.Repeat("-", widths[j]-1))
+		default:
+			b.WriteString(strings.Repeat("-", widths[j]))
+		}
+
+		b.WriteString(" ")
+	}
+
+	b.WriteString("|\n")
+
+	for i := range rows {
+		writeRow(rows[i])
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// markdownAlignment returns the pipe table alignment of c. Numeric columns are
+// right aligned by default, and decimal alignment is not supported.
+func markdownAlignment(c Column) Alignment {
+	switch {
+	case c.Align == AlignDecimal, c.Align == AlignAuto && c.isNumeric():
+		return AlignRight
+	default:
+		return c.Align
+	}
+}
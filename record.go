@@ -0,0 +1,224 @@
+package table
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// record is a row encoded with the column model. The keys are kept in column
+// order.
+type record struct {
+	keys   []string
+	values []any
+}
+
+// MarshalJSON implements the json.Marshaler interface for r.
+func (r record) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+
+	b.WriteByte('{')
+
+	for i := range r.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		key, err := json.Marshal(r.keys[i])
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(r.values[i])
+		if err != nil {
+			return nil, err
+		}
+
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+
+	b.WriteByte('}')
+
+	return b.Bytes(), nil
+}
+
+// MarshalYAML implements the yaml.InterfaceMarshaler interface for r.
+func (r record) MarshalYAML() (any, error) {
+	m := make(yaml.MapSlice, len(r.keys))
+	for i := range r.keys {
+		m[i] = yaml.MapItem{Key: r.keys[i], Value: r.values[i]}
+	}
+
+	return m, nil
+}
+
+// envelope is a section encoded with its column metadata and annotations.
+type envelope struct {
+	Type        string           `json:"type"                  yaml:"type"`
+	Columns     []envelopeColumn `json:"columns"               yaml:"columns"`
+	Rows        []record         `json:"rows"                  yaml:"rows"`
+	Annotations []Annotation     `json:"annotations,omitempty" yaml:"-"`
+	Totals      *record          `json:"totals,omitempty"      yaml:"totals,omitempty"`
+}
+
+type envelopeColumn struct {
+	Name   string   `json:"name"   yaml:"name"`
+	Key    string   `json:"key"    yaml:"key"`
+	Labels []string `json:"labels" yaml:"labels"`
+}
+
+// encodable returns the rows of the sections as they should be handed to the
+// JSON and YAML encoders. Unless WithColumnEncoding is set this is the structs
+// as written.
+func (t *Table) encodable(sections []Section) any {
+	if !t.byColumn && !slices.ContainsFunc(sections, t.hasTier) {
+		return sectionRows(sections)
+	}
+
+	sections = dataSections(sections)
+
+	if t.envelope {
+		envelopes := make([]envelope, len(sections))
+		for i := range sections {
+			envelopes[i] = t.sectionEnvelope(sections[i])
+		}
+
+		return envelopes
+	}
+
+	var rows []record
+
+	for _, s := range sections {
+		for _, row := range s.Cells {
+			rows = append(rows, t.record(s.Columns, row))
+		}
+	}
+
+	return rows
+}
+
+func (t *Table) sectionEnvelope(s Section) envelope {
+	e := envelope{
+		Type:        s.Type.Name(),
+		Columns:     []envelopeColumn{},
+		Rows:        make([]record, len(s.Cells)),
+		Annotations: s.Annotations,
+	}
+
+	for _, c := range s.Columns {
+		if c.isEncoded() {
+			e.Columns = append(e.Columns, envelopeColumn{
+				Name:   c.Name,
+				Key:    t.key(c),
+				Labels: c.valueLabels(),
+			})
+		}
+	}
+
+	for i := range s.Cells {
+		e.Rows[i] = t.record(s.Columns, s.Cells[i])
+	}
+
+	if s.Footer != nil {
+		var totals record
+
+		for j, c := range s.Columns {
+			if c.isEncoded() && c.Aggregate != nil && s.Footer[j].Value.IsValid() {
+				totals.keys = append(totals.keys, t.key(c))
+				totals.values = append(totals.values, s.Footer[j].Value.Interface())
+			}
+		}
+
+		e.Totals = &totals
+	}
+
+	return e
+}
+
+func (t *Table) record(columns []Column, row []Cell) record {
+	var r record
+
+	for j, c := range columns {
+		if !c.isEncoded() {
+			continue
+		}
+
+		r.keys = append(r.keys, t.key(c))
+		var value any // nil pointers and interfaces are invalid
+
+		if v := row[j].Value; v.IsValid() {
+			value = v.Interface()
+
+			if t.formattedValues && t.formatter(v.Type()) != nil {
+				value = row[j].Text
+			}
+		}
+
+		r.values = append(r.values, value)
+	}
+
+	return r
+}
+
+// key returns the JSON/YAML key for the column c.
+func (t *Table) key(c Column) string {
+	label := strings.Join(c.valueLabels(), " ")
+
+	if t.labelToKey != nil {
+		return t.labelToKey(label)
+	}
+
+	return label
+}
+
+// isEncoded returns true if c is part of the JSON and YAML column model.
+func (c Column) isEncoded() bool {
+	return c.exported && !c.IsZero
+}
+
+// dataSections returns the sections without the error rows for non-struct
+// values.
+func dataSections(sections []Section) []Section {
+	var s []Section
+
+	for i := range sections {
+		if sections[i].Type != reflect.TypeFor[writeError]() {
+			s = append(s, sections[i])
+		}
+	}
+
+	return s
+}
+
+// yamlComments returns the annotations of the enveloped sections as YAML
+// comments.
+func yamlComments(sections []Section) yaml.CommentMap {
+	cm := yaml.CommentMap{}
+
+	for i, s := range dataSections(sections) {
+		texts := map[int][]string{}
+
+		for _, a := range s.Annotations {
+			for line := range strings.SplitSeq(a.Text, "\n") {
+				texts[a.Index] = append(texts[a.Index], " "+line)
+			}
+		}
+
+		for index, lines := range texts {
+			if index < len(s.Cells) {
+				cm[fmt.Sprintf("$[%d].rows[%d]", i, index)] = []*yaml.Comment{yaml.HeadComment(lines...)}
+			} else {
+				cm[fmt.Sprintf("$[%d].rows", i)] = []*yaml.Comment{yaml.FootComment(lines...)}
+			}
+		}
+	}
+
+	return cm
+}
@@ -0,0 +1,171 @@
+package table
+
+import (
+	"cmp"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// sortKey is a column to sort by.
+type sortKey struct {
+	column     int
+	descending bool
+}
+
+// WithSort is an option setting function for New. It sorts the rows of each
+// section by the columns with the given header labels or struct field names. A
+// leading "-" sorts the column in descending order, for example
+// WithSort("ZONE", "-RANK"). It replaces the "sort=N" options of the "table"
+// struct tags.
+//
+// Numbers, times and durations are compared by value and strings are compared
+// lexically, or naturally with WithNaturalSort. The sort is stable, so rows
+// with equal keys keep the order in which they were written.
+func WithSort(columns ...string) func(*Table) {
+	return func(t *Table) {
+		t.sortBy = columns
+	}
+}
+
+// WithNaturalSort is an option setting function for New. It sorts strings with
+// runs of digits compared as numbers, so "compute-0-10" sorts after
+// "compute-0-9".
+func WithNaturalSort() func(*Table) {
+	return func(t *Table) {
+		t.naturalSort = true
+	}
+}
+
+// sortKeys returns the columns to sort by, from WithSort or the "sort=N" tag
+// options. A negative N sorts the column in descending order.
+func (t *Table) sortKeys(info []Column) []sortKey {
+	var keys []sortKey
+
+	if t.sortBy != nil {
+		for _, name := range t.sortBy {
+			name, descending := strings.CutPrefix(name, "-")
+
+			for j := range info {
+				if info[j].matches(name) {
+					keys = append(keys, sortKey{column: j, descending: descending})
+				}
+			}
+		}
+
+		return keys
+	}
+
+	for j := range info {
+		if info[j].sort != 0 {
+			keys = append(keys, sortKey{column: j, descending: info[j].sort < 0})
+		}
+	}
+
+	slices.SortStableFunc(keys, func(a, b sortKey) int {
+		return cmp.Compare(abs(info[a.column].sort), abs(info[b.column].sort))
+	})
+
+	return keys
+}
+
+// sort orders the rows of s by the sort keys.
+func (t *Table) sort(s *Section) {
+	keys := t.sortKeys(s.Columns)
+	if len(keys) == 0 {
+		return
+	}
+
+	order := make([]int, len(s.Cells))
+	for i := range order {
+		order[i] = i
+	}
+
+	slices.SortStableFunc(order, func(a, b int) int {
+		for _, k := range keys {
+			c := t.compare(s.Cells[a][k.column].Value, s.Cells[b][k.column].Value)
+			if k.descending {
+				c = -c
+			}
+
+			if c != 0 {
+				return c
+			}
+		}
+
+		return 0
+	})
+
+	reorder(s, order)
+}
+
+func (t *Table) compare(a, b reflect.Value) int {
+	if t.naturalSort && a.Kind() == reflect.String && b.Kind() == reflect.String {
+		return naturalCompare(a.String(), b.String())
+	}
+
+	return compareValues(a, b)
+}
+
+// naturalCompare compares strings with runs of digits compared by their
+// numeric value.
+func naturalCompare(a, b string) int {
+	x, y := a, b
+
+	for x != "" && y != "" {
+		if isDigit(x[0]) && isDigit(y[0]) {
+			var nx, ny string
+
+			nx, x = splitDigits(x)
+			ny, y = splitDigits(y)
+
+			// Without leading zeros the longer number is the larger one.
+
+			tx, ty := strings.TrimLeft(nx, "0"), strings.TrimLeft(ny, "0")
+
+			if c := cmp.Compare(len(tx), len(ty)); c != 0 {
+				return c
+			}
+
+			if c := cmp.Compare(tx, ty); c != 0 {
+				return c
+			}
+
+			continue
+		}
+
+		if c := cmp.Compare(x[0], y[0]); c != 0 {
+			return c
+		}
+
+		x, y = x[1:], y[1:]
+	}
+
+	if c := cmp.Compare(len(x), len(y)); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(a, b) // "01" and "1" are ordered by their text
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// splitDigits splits s after its leading run of digits.
+func splitDigits(s string) (digits, rest string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+
+	return s[:i], s[i:]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
@@ -0,0 +1,141 @@
+package table
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"endobit.io/table/sgr"
+)
+
+// styleRule styles the cells matching a "color" tag option rule.
+type styleRule struct {
+	op     string  // "*", "=" for the text, or a comparison of the number
+	text   string  // text to match for "="
+	number float64 // number to compare with
+	style  []sgr.Param
+}
+
+// styleNames are the names used in "color" tag option rules.
+var styleNames = map[string][]sgr.Param{
+	"bold":      {sgr.Bold},
+	"faint":     {sgr.Faint},
+	"italic":    {sgr.Italic},
+	"underline": {sgr.Underline},
+	"reverse":   {sgr.ReverseVideo},
+	"black":     {sgr.Black.FG()},
+	"red":       {sgr.Red.FG()},
+	"green":     {sgr.Green.FG()},
+	"yellow":    {sgr.Yellow.FG()},
+	"blue":      {sgr.Blue.FG()},
+	"magenta":   {sgr.Magenta.FG()},
+	"cyan":      {sgr.Cyan.FG()},
+	"white":     {sgr.White.FG()},
+}
+
+// WithCellStyle is an option setting function for New. The function fn returns
+// the style of a text cell from its column and value, or nil to use the
+// "color" tag option rules of the column and then the wrapper interface of
+// the value. The value is invalid for nil pointers and interfaces.
+//
+// Styles, like all colors, are only applied when the output is a terminal.
+func WithCellStyle(fn func(col Column, v reflect.Value) []sgr.Param) func(*Table) {
+	return func(t *Table) {
+		t.cellStyle = fn
+	}
+}
+
+// parseStyleRules parses the rules of a "color" tag option, separated by "|".
+// Each rule is a pattern and a style joined by ":", where the pattern is text
+// to match, a comparison with a number such as ">90", or "*" to match anything.
+// The style is color and style names joined by "+", such as "bold+red". Rules
+// that can't be parsed are ignored.
+func parseStyleRules(s string) []styleRule {
+	var rules []styleRule
+
+	for r := range strings.SplitSeq(s, "|") {
+		pattern, names, ok := strings.Cut(r, ":")
+		if !ok {
+			continue
+		}
+
+		var rule styleRule
+
+		for name := range strings.SplitSeq(names, "+") {
+			style, ok := styleNames[strings.ToLower(strings.TrimSpace(name))]
+			if !ok {
+				rule.style = nil
+
+				break
+			}
+
+			rule.style = append(rule.style, style...)
+		}
+
+		if rule.style == nil {
+			continue
+		}
+
+		rule.op, rule.text = "=", pattern
+
+		if pattern == "*" {
+			rule.op = "*"
+		}
+
+		for _, op := range []string{">=", "<=", ">", "<"} {
+			if n, ok := strings.CutPrefix(pattern, op); ok {
+				if x, err := strconv.ParseFloat(strings.TrimSpace(n), 64); err == nil {
+					rule.op, rule.number = op, x
+				}
+
+				break
+			}
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// match returns true if the rule matches the cell. Numbers are compared with
+// the value, not the formatted text.
+func (r styleRule) match(cell Cell) bool {
+	if r.op == "*" || r.op == "=" {
+		return r.op == "*" || cell.Text == r.text
+	}
+
+	x, ok := number(cell.Value)
+	if !ok {
+		return false
+	}
+
+	switch r.op {
+	case ">=":
+		return x >= r.number
+	case "<=":
+		return x <= r.number
+	case ">":
+		return x > r.number
+	default:
+		return x < r.number
+	}
+}
+
+// style returns the style of a cell from WithCellStyle or the "color" tag
+// option rules, or nil if it has none.
+func (t *Table) style(c Column, cell Cell) []sgr.Param {
+	if t.cellStyle != nil {
+		if style := t.cellStyle(c, cell.Value); style != nil {
+			return style
+		}
+	}
+
+	for _, r := range c.styles {
+		if r.match(cell) {
+			return r.style
+		}
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@
 package table
 
 import (
+	"encoding"
 	"errors"
 	"fmt"
 	"io"
@@ -10,8 +11,8 @@ import (
 	"reflect"
 	"strings"
 	"unicode"
-	"unicode/utf8"
 
+	"github.com/rivo/uniseg"
 	"golang.org/x/term"
 
 	"endobit.io/table/sgr"
@@ -21,14 +22,6 @@ import (
 // struct.
 var ErrNotStruct = errors.New("not a struct")
 
-type style int
-
-const (
-	textOutput style = iota
-	jsonOutput
-	yamlOutput
-)
-
 // Colors is the set styles/colors to be applied to Table elements.
 type Colors struct {
 	Header     []sgr.Param
@@ -37,23 +30,56 @@ type Colors struct {
 	Empty      []sgr.Param
 	Repeat     []sgr.Param
 	Annotation []sgr.Param
+	Footer     []sgr.Param
+	Group      []sgr.Param
 }
 
 // Table holds a slice of structs that can be Flush()ed as a Text table, or
-// encoded as JSON or YAML.
+// encoded as JSON, YAML, CSV, TSV, Markdown or HTML.
 type Table struct {
-	rows         []any
-	annotations  []annotation
-	colors       Colors
-	noColor      bool
-	writer       io.Writer
-	style        style
-	fieldToLabel func(string) string
+	rows            []any
+	annotations     []Annotation
+	colors          Colors
+	noColor         bool
+	writer          io.Writer
+	format          Format
+	fieldToLabel    func(string) string
+	labelToKey      func(string) string
+	byColumn        bool
+	envelope        bool
+	alignNumbers    bool
+	maxWidth        int
+	border          *Border
+	rowRules        int
+	sectionRules    bool
+	vertical        bool
+	autoVertical    bool
+	footers         []columnFooter
+	groupBy         []string
+	sortBy          []string
+	naturalSort     bool
+	filter          filter
+	columns         []string
+	hiddenColumns   []string
+	tiers           []string
+	formatters      map[reflect.Type]typeFormatter
+	formattedValues bool
+	locale          *locale
+	cellStyle       func(Column, reflect.Value) []sgr.Param
+	err             error
+}
+
+// writeError is the row written in place of a value that is not a struct.
+type writeError struct {
+	Error error
+	Type  string
+	Value string
 }
 
-type annotation struct {
-	index int
-	text  string
+// Annotation is a line of text inserted before the row at Index.
+type Annotation struct {
+	Index int    `json:"index" yaml:"index"`
+	Text  string `json:"text"  yaml:"text"`
 }
 
 // WithColor is an option setting function for New. It replaces the default set
@@ -84,17 +110,53 @@ func WithLabelFunction(fn func(string) string) func(*Table) {
 	}
 }
 
+// WithColumnEncoding is an option setting function for New. It makes the JSON
+// and YAML output use the same column model as the text output: the header
+// labels are the keys, in the same order, omitempty columns are dropped, and
+// unexported fields and rows for non-struct values are skipped.
+func WithColumnEncoding() func(*Table) {
+	return func(t *Table) {
+		t.byColumn = true
+	}
+}
+
+// WithKeyFunction is an option setting function for New. This function
+// converts header labels into JSON and YAML keys, for example strings.ToLower.
+// It implies WithColumnEncoding.
+func WithKeyFunction(fn func(string) string) func(*Table) {
+	return func(t *Table) {
+		t.byColumn = true
+		t.labelToKey = fn
+	}
+}
+
+// WithEnvelope is an option setting function for New. It makes the JSON and
+// YAML output a list of sections, one for each struct type, rather than a flat
+// list of rows. Each section has the struct type name, its column metadata, its
+// rows, its annotations indexed by row, and the totals of the columns with a
+// footer Aggregate. YAML writes the annotations as comments. It implies
+// WithColumnEncoding.
+func WithEnvelope() func(*Table) {
+	return func(t *Table) {
+		t.byColumn = true
+		t.envelope = true
+	}
+}
+
 // New returns a new Table. The default settings can be overridden using the
 // With* options setting functions. For example: WithColors() can be used to
 // replace the default coloring scheme.
 func New(opts ...func(*Table)) *Table {
 	t := Table{
 		writer: os.Stdout,
+		format: FormatText,
 		colors: Colors{
 			Header:     []sgr.Param{sgr.Underline, sgr.Bold},
 			Empty:      []sgr.Param{sgr.Faint},
 			Repeat:     []sgr.Param{sgr.Faint},
 			Annotation: []sgr.Param{sgr.Italic},
+			Footer:     []sgr.Param{sgr.Bold},
+			Group:      []sgr.Param{sgr.Bold},
 		},
 		fieldToLabel: camelToUpperSnake,
 	}
@@ -111,33 +173,39 @@ func New(opts ...func(*Table)) *Table {
 	return &t
 }
 
-// Write appends the struct a to the table as a row. The current table will be
-// flushed if a new struct type is written. If a is not a struct, an error table
-// will be added to the output.
+// Write appends the struct a, or the struct a points to, to the table as a row.
+// The current table will be flushed if a new struct type is written. If a is
+// not a struct, or is a nil pointer, an error table will be added to the
+// output.
 func (t *Table) Write(a any) {
-	if reflect.TypeOf(a).Kind() != reflect.Struct {
-		msg := struct {
-			Error error
-			Type  string
-			Value string
-		}{
+	v := reflect.ValueOf(a)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		msg := writeError{
 			Error: ErrNotStruct,
 			Type:  fmt.Sprintf("%T", a),
-			Value: valueAsString(reflect.ValueOf(a)),
+			Value: valueAsString(v),
 		}
 
 		t.rows = append(t.rows, msg)
 
-		t.Annotate(fmt.Sprintf("skipping non-struct type: %T", a))
+		if v.Kind() == reflect.Pointer {
+			t.Annotate(fmt.Sprintf("skipping nil %T", a))
+		} else {
+			t.Annotate(fmt.Sprintf("skipping non-struct type: %T", a))
+		}
 
 		return
 	}
 
-	t.rows = append(t.rows, a)
+	t.rows = append(t.rows, v.Interface())
 }
 
 // Clear removes all rows and annotations from the table, allowing it to be
-// reused. Configuration settings (colors, writer, style, label function) are
+// reused. Configuration settings (colors, writer, format, label function) are
 // preserved.
 func (t *Table) Clear() {
 	t.rows = nil
@@ -148,27 +216,28 @@ func (t *Table) Clear() {
 // inserting comments or other information that is not a struct. The string will
 // be printed as-is, without any formatting or coloring.
 //
-// Annotations are only used in text output, and are ignored in JSON or YAML
-// formats.
+// Annotations are used in text, Markdown and HTML output. They are ignored in
+// CSV and TSV formats, and in JSON and YAML unless WithEnvelope is set.
 func (t *Table) Annotate(s string) {
-	t.annotations = append(t.annotations, annotation{
-		index: len(t.rows),
-		text:  s,
+	t.annotations = append(t.annotations, Annotation{
+		Index: len(t.rows),
+		Text:  s,
 	})
 }
 
-// Flush writes the table to its writer in its default style.
+// Flush writes the table to its writer in its default format. The format is
+// text unless changed with one of the As* options.
 func (t *Table) Flush() error {
-	switch t.style {
-	case jsonOutput:
-		return t.FlushJSON()
-	case yamlOutput:
-		return t.FlushYAML()
-	default:
-		t.FlushText()
+	if t.err != nil {
+		return t.err
+	}
+
+	factory, err := lookupFormat(t.format)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return factory(t).Encode(t.writer, t.sections())
 }
 
 func isTerminal(w io.Writer) bool {
@@ -183,7 +252,7 @@ func isTerminal(w io.Writer) bool {
 func maxStringLength(list []string) int {
 	maxLen := 0
 	for _, s := range list {
-		if l := utf8.RuneCountInString(s); l > maxLen {
+		if l := displayWidth(s); l > maxLen {
 			maxLen = l
 		}
 	}
@@ -191,12 +260,37 @@ func maxStringLength(list []string) int {
 	return maxLen
 }
 
+// displayWidth returns the number of terminal cells needed to display s. Wide
+// East Asian characters and emoji take two cells, and combining marks and
+// zero width joiner sequences are measured as a single grapheme cluster.
+func displayWidth(s string) int {
+	return uniseg.StringWidth(s)
+}
+
+// valueAsString formats v with its Error, String or MarshalText method, in that
+// order, or with the fmt package. A nil pointer or interface is "<nil>", as the
+// methods may not accept it.
 func valueAsString(v reflect.Value) string {
-	if v.IsValid() && v.CanInterface() {
-		return fmt.Sprintf("%v", v.Interface())
+	if !v.IsValid() || !v.CanInterface() {
+		return ""
+	}
+
+	if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+		return "<nil>"
+	}
+
+	switch a := v.Interface().(type) {
+	case error:
+		return a.Error()
+	case fmt.Stringer:
+		return a.String()
+	case encoding.TextMarshaler:
+		if text, err := a.MarshalText(); err == nil {
+			return string(text)
+		}
 	}
 
-	return ""
+	return fmt.Sprintf("%v", v.Interface())
 }
 
 // camelToUpperSnake converts a CamelCase string to UPPERCASE_SNAKE_CASE,
@@ -2,9 +2,16 @@ package table
 
 import (
 	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/netip"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"endobit.io/table/sgr"
 	"endobit.io/table/sgr/color"
@@ -13,6 +20,10 @@ import (
 func init() {
 	// Disable colors for reproducible example output
 	sgr.DisableColor()
+
+	RegisterFormat("logfmt", func(*Table) Encoder {
+		return EncoderFunc(logfmt)
+	})
 }
 
 type rank int
@@ -586,3 +597,1744 @@ func TestClearPreservesConfiguration(t *testing.T) {
 		t.Errorf("expected lowercase 'name' header after Clear, got: %q", secondOutput)
 	}
 }
+
+func ExampleTable_FlushCSV() {
+	var buf bytes.Buffer
+
+	t := New(WithWriter(&buf))
+
+	t.Write(server{Name: "web-1", Status: "running", Port: 8080})
+	t.Write(server{Name: "web, 2", Status: "stopped", Port: 8081})
+	_ = t.FlushCSV()
+
+	fmt.Print(buf.String())
+	// Output:
+	// NAME,STATUS,PORT
+	// web-1,running,8080
+	// "web, 2",stopped,8081
+}
+
+func TestTSVOutput(t *testing.T) {
+	var buf bytes.Buffer
+
+	tbl := New(AsTSV(), WithWriter(&buf))
+
+	tbl.Write(host{Zone: "east", Cluster: "prod", Host: "compute-0-0", Rank: 1})
+	tbl.Annotate("ignored")
+	tbl.Write(person{Name: "Alice", Age: 30})
+
+	if err := tbl.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	want := "ZONE\tCLUSTER\tHOST\tRANK\n" +
+		"east\tprod\tcompute-0-0\t1\n" +
+		"\n" +
+		"NAME\tAGE\n" +
+		"Alice\t30\n"
+
+	if got := buf.String(); got != want {
+		t.Errorf("FlushTSV() = %q; want %q", got, want)
+	}
+}
+
+func TestAnnotationsAcrossTypes(t *testing.T) {
+	var buf bytes.Buffer
+
+	tbl := New(WithWriter(&buf))
+
+	tbl.Annotate("first")
+	tbl.Write(person{Name: "Alice", Age: 30})
+	tbl.Annotate("second")
+	tbl.Write(server{Name: "web-1", Status: "running", Port: 8080})
+	tbl.Annotate("last")
+	_ = tbl.Flush()
+
+	want := "NAME  AGE\n" +
+		"first\n" +
+		"Alice 30\n" +
+		"NAME  STATUS  PORT\n" +
+		"second\n" +
+		"web-1 running 8080\n" +
+		"last\n"
+
+	if got := buf.String(); got != want {
+		t.Errorf("Flush() = %q; want %q", got, want)
+	}
+}
+
+func ExampleTable_FlushMarkdown() {
+	var buf bytes.Buffer
+
+	t := New(AsMarkdown(), WithWriter(&buf))
+
+	t.Write(server{Name: "web-1", Status: "running", Port: 8080})
+	t.Annotate("maintenance window")
+	t.Write(server{Name: "web|2", Status: "stopped", Port: 443})
+	_ = t.Flush()
+
+	fmt.Print(buf.String())
+	// Output:
+	// | NAME   | STATUS  | PORT |
+	// | ------ | ------- | ---: |
+	// | web-1  | running | 8080 |
+	//
+	// maintenance window
+	//
+	// | NAME   | STATUS  | PORT |
+	// | ------ | ------- | ---: |
+	// | web\|2 | stopped |  443 |
+}
+
+func TestMarkdownEscaping(t *testing.T) {
+	type note struct {
+		Title string `table:"TITLE\n(text)"`
+		Body  string
+	}
+
+	var buf bytes.Buffer
+
+	tbl := New(WithWriter(&buf))
+
+	tbl.Write(note{Title: "a", Body: "line 1\nline 2"})
+
+	if err := tbl.FlushMarkdown(); err != nil {
+		t.Fatalf("FlushMarkdown() error = %v", err)
+	}
+
+	want := "| TITLE<br>(text) | BODY             |\n" +
+		"| --------------- | ---------------- |\n" +
+		"| a               | line 1<br>line 2 |\n"
+
+	if got := buf.String(); got != want {
+		t.Errorf("FlushMarkdown() = %q; want %q", got, want)
+	}
+}
+
+func ExampleTable_FlushHTML() {
+	var buf bytes.Buffer
+
+	t := New(AsHTML(), WithWriter(&buf))
+
+	t.Write(host{Zone: "east", Cluster: "prod", Host: "compute-0-0", Rank: 0})
+	t.Annotate("<maintenance>")
+	t.Write(host{Zone: "east", Cluster: "", Host: "compute-0-1", Rank: 1})
+	t.Write(person{Name: "Alice", Age: 30})
+	_ = t.Flush()
+
+	fmt.Print(buf.String())
+	// Output:
+	// <table>
+	// <thead>
+	// <tr class="header"><th>ZONE</th><th>CLUSTER</th><th>HOST</th><th>RANK</th></tr>
+	// </thead>
+	// <tbody>
+	// <tr class="even-row"><td>east</td><td>prod</td><td>compute-0-0</td><td>0</td></tr>
+	// <tr class="annotation"><td colspan="4">&lt;maintenance&gt;</td></tr>
+	// <tr class="odd-row"><td class="repeat">east</td><td class="empty"></td><td>compute-0-1</td><td>1</td></tr>
+	// </tbody>
+	// </table>
+	// <table>
+	// <thead>
+	// <tr class="header"><th>NAME</th><th>AGE</th></tr>
+	// </thead>
+	// <tbody>
+	// <tr class="even-row"><td>Alice</td><td>30</td></tr>
+	// </tbody>
+	// </table>
+}
+
+// logfmt is an example third-party Encoder.
+func logfmt(w io.Writer, sections []Section) error {
+	for _, s := range sections {
+		for _, row := range s.Cells {
+			pairs := make([]string, len(row))
+			for j := range row {
+				pairs[j] = strings.ToLower(s.Columns[j].Labels[0]) + "=" + row[j].Text
+			}
+
+			fmt.Fprintln(w, strings.Join(pairs, " "))
+		}
+	}
+
+	return nil
+}
+
+func ExampleParseFormat() {
+	f, err := ParseFormat("LOGFMT")
+	if err != nil {
+		fmt.Println(err)
+
+		return
+	}
+
+	var buf bytes.Buffer
+
+	t := New(AsFormat(f), WithWriter(&buf))
+
+	t.Write(server{Name: "web-1", Status: "running", Port: 8080})
+	_ = t.Flush()
+
+	fmt.Print(buf.String())
+	// Output:
+	// name=web-1 status=running port=8080
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, name := range Formats() {
+		f, err := ParseFormat(strings.ToUpper(name))
+		if err != nil {
+			t.Errorf("ParseFormat(%q) error = %v", name, err)
+		}
+
+		if string(f) != name {
+			t.Errorf("ParseFormat(%q) = %q; want %q", name, f, name)
+		}
+	}
+
+	if _, err := ParseFormat("bogus"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("ParseFormat(%q) error = %v; want %v", "bogus", err, ErrUnknownFormat)
+	}
+
+	tbl := New(AsFormat("bogus"), WithWriter(io.Discard))
+	tbl.Write(person{Name: "Alice", Age: 30})
+
+	if err := tbl.Flush(); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Flush() error = %v; want %v", err, ErrUnknownFormat)
+	}
+}
+
+func ExampleWithColumnEncoding() {
+	type node struct {
+		Host     string `table:"HOST"`
+		Rack     string `table:"RACK,omitempty"`
+		Rank     rank   `table:"RANK"`
+		internal string
+	}
+
+	var buf bytes.Buffer
+
+	t := New(AsJSON(), WithWriter(&buf), WithKeyFunction(strings.ToLower))
+
+	t.Write(node{Host: "compute-0-0", Rank: 1, internal: "x"})
+	t.Write("not a struct")
+	_ = t.Flush()
+
+	fmt.Print(buf.String())
+	// Output:
+	// [
+	//     {
+	//         "host": "compute-0-0",
+	//         "rank": 1
+	//     }
+	// ]
+}
+
+func TestColumnEncodingYAML(t *testing.T) {
+	var buf bytes.Buffer
+
+	tbl := New(AsYAML(), WithWriter(&buf), WithColumnEncoding())
+
+	tbl.Write(host{Zone: "east", Cluster: "prod", Host: "compute-0-0", Rank: 3})
+
+	if err := tbl.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	want := "- ZONE: east\n  CLUSTER: prod\n  HOST: compute-0-0\n  RANK: 3\n"
+
+	if got := buf.String(); got != want {
+		t.Errorf("FlushYAML() = %q; want %q", got, want)
+	}
+}
+
+func ExampleWithEnvelope() {
+	var buf bytes.Buffer
+
+	t := New(AsYAML(), WithWriter(&buf), WithEnvelope(), WithKeyFunction(strings.ToLower))
+
+	t.Write(server{Name: "web-1", Status: "running", Port: 8080})
+	t.Annotate("maintenance window")
+	t.Write(server{Name: "web-2", Status: "stopped", Port: 8081})
+	t.Write(person{Name: "Alice", Age: 30})
+	t.Annotate("end of report")
+	_ = t.Flush()
+
+	fmt.Print(buf.String())
+	// Output:
+	// - type: server
+	//   columns:
+	//   - name: Name
+	//     key: name
+	//     labels:
+	//     - NAME
+	//   - name: Status
+	//     key: status
+	//     labels:
+	//     - STATUS
+	//   - name: Port
+	//     key: port
+	//     labels:
+	//     - PORT
+	//   rows:
+	//   - name: web-1
+	//     status: running
+	//     port: 8080
+	//   # maintenance window
+	//   - name: web-2
+	//     status: stopped
+	//     port: 8081
+	// - type: person
+	//   columns:
+	//   - name: Name
+	//     key: name
+	//     labels:
+	//     - NAME
+	//   - name: Age
+	//     key: age
+	//     labels:
+	//     - AGE
+	//   rows:
+	//   - name: Alice
+	//     age: 30
+	//   # end of report
+}
+
+func TestEnvelopeJSON(t *testing.T) {
+	var buf bytes.Buffer
+
+	tbl := New(AsJSON(), WithWriter(&buf), WithEnvelope())
+
+	tbl.Write(person{Name: "Alice", Age: 30})
+	tbl.Annotate("note")
+	tbl.Write(person{Name: "Bob", Age: 25})
+
+	if err := tbl.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	var got []struct {
+		Type    string
+		Columns []struct {
+			Name   string
+			Key    string
+			Labels []string
+		}
+		Rows        []map[string]any
+		Annotations []Annotation
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v\n%s", err, buf.String())
+	}
+
+	if len(got) != 1 || got[0].Type != "person" {
+		t.Fatalf("expected one person section, got: %s", buf.String())
+	}
+
+	if len(got[0].Columns) != 2 || got[0].Columns[1].Key != "AGE" {
+		t.Errorf("unexpected columns: %+v", got[0].Columns)
+	}
+
+	if len(got[0].Rows) != 2 || got[0].Rows[1]["NAME"] != "Bob" {
+		t.Errorf("unexpected rows: %+v", got[0].Rows)
+	}
+
+	want := []Annotation{{Index: 1, Text: "note"}}
+	if !reflect.DeepEqual(got[0].Annotations, want) {
+		t.Errorf("annotations = %+v; want %+v", got[0].Annotations, want)
+	}
+}
+
+func ExampleWithNumericAlignment() {
+	type disk struct {
+		Device string  `table:"DEVICE"`
+		Model  string  `table:"MODEL,align=center"`
+		Bytes  int64   `table:"BYTES"`
+		Load   float64 `table:"LOAD"`
+	}
+
+	var buf bytes.Buffer
+
+	t := New(WithWriter(&buf), WithNumericAlignment())
+
+	t.Write(disk{Device: "sda", Model: "ssd", Bytes: 512, Load: 0.5})
+	t.Write(disk{Device: "sdb", Model: "nvme", Bytes: 1073741824, Load: 12.25})
+	t.Write(disk{Device: "sdc", Model: "hdd", Bytes: 4096, Load: 3})
+	_ = t.Flush()
+
+	fmt.Print(buf.String())
+	// Output:
+	// DEVICE MODEL      BYTES  LOAD
+	// sda     ssd         512  0.5
+	// sdb    nvme  1073741824 12.25
+	// sdc     hdd        4096  3
+}
+
+func TestDisplayWidth(t *testing.T) {
+	type site struct {
+		Name string `table:"NAME"`
+		User string `table:"USER"`
+		Tag  string `table:"TAG"`
+	}
+
+	var buf bytes.Buffer
+
+	tbl := New(WithWriter(&buf))
+
+	tbl.Write(site{Name: "東京", User: "Jose\u0301", Tag: "👍🏽"})
+	tbl.Write(site{Name: "osaka-1", User: "Zoë", Tag: "👨‍👩‍👧"})
+	tbl.Write(site{Name: "ny", User: "bob", Tag: "ok"})
+	_ = tbl.Flush()
+
+	want := "NAME    USER TAG\n" +
+		"東京    Jose\u0301 👍🏽\n" +
+		"osaka-1 Zoë  👨‍👩‍👧\n" +
+		"ny      bob  ok\n"
+
+	if got := buf.String(); got != want {
+		t.Errorf("Flush() = %q; want %q", got, want)
+	}
+}
+
+func ExampleWithMaxWidth() {
+	type service struct {
+		Name        string `table:"NAME"`
+		Path        string `table:"PATH,truncate=middle"`
+		Description string `table:"DESCRIPTION,wrap"`
+		Owner       string `table:"OWNER,priority=1"`
+	}
+
+	var buf bytes.Buffer
+
+	t := New(WithWriter(&buf), WithMaxWidth(28))
+
+	t.Write(service{
+		Name:        "api",
+		Path:        "/srv/services/api/bin/server",
+		Description: "public REST endpoint for the inventory",
+		Owner:       "platform",
+	})
+	t.Write(service{Name: "db", Path: "/srv/db", Description: "postgres", Owner: "storage"})
+	_ = t.Flush()
+
+	fmt.Print(buf.String())
+	// Output:
+	// NAME PATH        DESCRIPTION
+	// api  /srv/…erver public REST
+	//                  endpoint
+	//                  for the
+	//                  inventory
+	// db   /srv/db     postgres
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		input    string
+		overflow overflow
+		expected string
+	}{
+		{"compute-0-10", overflowTruncateEnd, "compu…"},
+		{"compute-0-10", overflowTruncateStart, "…-0-10"},
+		{"compute-0-10", overflowTruncateMiddle, "com…10"},
+		{"東京データセンター", overflowTruncateEnd, "東京…"},
+		{"short", overflowTruncateEnd, "short"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := truncate(tt.input, 6, tt.overflow); got != tt.expected {
+				t.Errorf("truncate(%q, 6) = %q; want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTruncateDecimal(t *testing.T) {
+	type load struct {
+		Name string  `table:"NAME"`
+		Load float64 `table:"LOAD,truncate"`
+	}
+
+	var buf bytes.Buffer
+
+	tbl := New(WithWriter(&buf), WithNumericAlignment(), WithMaxWidth(12))
+
+	tbl.Write(load{Name: "a", Load: 12345.678901})
+	tbl.Write(load{Name: "b", Load: 1.5})
+
+	if err := tbl.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "NAME    LOAD\na    12345.…\nb        1.5\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func ExampleWithBorder() {
+	var buf bytes.Buffer
+
+	t := New(WithWriter(&buf), WithBorder(BorderLight))
+
+	t.Write(server{Name: "web-1", Status: "running", Port: 8080})
+	t.Annotate("maintenance")
+	t.Write(server{Name: "web-2", Status: "stopped", Port: 8081})
+	_ = t.Flush()
+
+	fmt.Print(buf.String())
+	// Output:
+	// ┌───────┬─────────┬──────┐
+	// │ NAME  │ STATUS  │ PORT │
+	// ├───────┼─────────┼──────┤
+	// │ web-1 │ running │ 8080 │
+	// │ maintenance            │
+	// │ web-2 │ stopped │ 8081 │
+	// └───────┴─────────┴──────┘
+}
+
+func ExampleWithBorder_minimal() {
+	var buf bytes.Buffer
+
+	t := New(WithWriter(&buf), WithBorder(BorderMinimal), WithRowRules(1))
+
+	t.Write(server{Name: "web-1", Status: "running", Port: 8080})
+	t.Write(server{Name: "web-2", Status: "stopped", Port: 8081})
+	_ = t.Flush()
+
+	fmt.Print(buf.String())
+	// Output:
+	// NAME  | STATUS  | PORT
+	// ------+---------+-----
+	// web-1 | running | 8080
+	// ------+---------+-----
+	// web-2 | stopped | 8081
+}
+
+func TestSectionRules(t *testing.T) {
+	var buf bytes.Buffer
+
+	tbl := New(WithWriter(&buf), WithSectionRules())
+
+	tbl.Write(person{Name: "Alice", Age: 30})
+	tbl.Write(server{Name: "web-1", Status: "running", Port: 8080})
+	_ = tbl.Flush()
+
+	want := "NAME  AGE\n" +
+		"Alice 30\n" +
+		"----- ------- ----\n" +
+		"NAME  STATUS  PORT\n" +
+		"web-1 running 8080\n"
+
+	if got := buf.String(); got != want {
+		t.Errorf("Flush() = %q; want %q", got, want)
+	}
+}
+
+func ExampleWithVertical() {
+	var buf bytes.Buffer
+
+	t := New(WithWriter(&buf), WithVertical())
+
+	t.Write(host{Zone: "east", Cluster: "prod", Host: "compute-0-0", Rank: 0})
+	t.Annotate("maintenance")
+	t.Write(host{Zone: "east", Host: "compute-0-1", Rank: 1})
+	_ = t.Flush()
+
+	fmt.Print(buf.String())
+	// Output:
+	// -[ RECORD 1 ]------
+	// ZONE    east
+	// CLUSTER prod
+	// HOST    compute-0-0
+	// RANK    0
+	// maintenance
+	// -[ RECORD 2 ]------
+	// ZONE    east
+	// CLUSTER -
+	// HOST    compute-0-1
+	// RANK    1
+}
+
+func TestAutoVertical(t *testing.T) {
+	var buf bytes.Buffer
+
+	tbl := New(WithWriter(&buf), WithAutoVertical(), WithMaxWidth(15))
+
+	tbl.Write(person{Name: "Alice", Age: 30})
+	tbl.Write(server{Name: "web-1", Status: "running", Port: 8080})
+	_ = tbl.Flush()
+
+	want := "NAME  AGE\n" +
+		"Alice 30\n" +
+		"-[ RECORD 1 ]-\n" +
+		"NAME   web-1\n" +
+		"STATUS running\n" +
+		"PORT   8080\n"
+
+	if got := buf.String(); got != want {
+		t.Errorf("Flush() = %q; want %q", got, want)
+	}
+}
+
+func ExampleWithFooter() {
+	type volume struct {
+		Name  string        `table:"NAME"`
+		Bytes int64         `table:"BYTES,sum,align=right"`
+		Load  float64       `table:"LOAD,avg"`
+		Age   time.Duration `table:"AGE"`
+	}
+
+	var buf bytes.Buffer
+
+	t := New(WithWriter(&buf), WithFooter("AGE", Max))
+
+	t.Write(volume{Name: "vol-1", Bytes: 512, Load: 0.5, Age: time.Hour})
+	t.Write(volume{Name: "vol-2", Bytes: 4096, Load: 1.5, Age: 90 * time.Minute})
+	_ = t.Flush()
+
+	fmt.Print(buf.String())
+	// Output:
+	// NAME  BYTES LOAD AGE
+	// vol-1   512 0.5  1h0m0s
+	// vol-2  4096 1.5  1h30m0s
+	// ----- ----- ---- -------
+	// TOTAL  4608 1    1h30m0s
+}
+
+func TestFooterAverage(t *testing.T) {
+	type volume struct {
+		Name  string        `table:"NAME"`
+		Bytes int64         `table:"BYTES,avg"`
+		Age   time.Duration `table:"AGE,avg"`
+	}
+
+	var buf bytes.Buffer
+
+	tbl := New(WithWriter(&buf))
+
+	tbl.Write(volume{Name: "vol-1", Bytes: 2000000, Age: time.Second})
+	tbl.Write(volume{Name: "vol-2", Bytes: 3000000, Age: 2 * time.Second})
+
+	if err := tbl.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if got, want := lines[len(lines)-1], "TOTAL 2500000 1.5s"; got != want {
+		t.Errorf("footer = %q; want %q", got, want)
+	}
+}
+
+func TestSumMixedKinds(t *testing.T) {
+	tests := []struct {
+		values []any
+		want   any
+	}{
+		{[]any{1, 2}, 3},
+		{[]any{1, "x"}, nil},
+		{[]any{1, uint(2)}, nil},
+		{[]any{uint8(1), 2.5}, nil},
+		{[]any{1.5, 2}, nil},
+	}
+
+	for _, tt := range tests {
+		values := make([]reflect.Value, len(tt.values))
+		for i, v := range tt.values {
+			values[i] = reflect.ValueOf(v)
+		}
+
+		if got := Sum(values); got != tt.want {
+			t.Errorf("Sum(%v) = %v, want %v", tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestFooterFloat(t *testing.T) {
+	type sample struct {
+		Name  string  `table:"NAME"`
+		Value float64 `table:"VALUE,max"`
+		Avg   float64 `table:"AVG,avg"`
+	}
+
+	var buf bytes.Buffer
+
+	tbl := New(WithWriter(&buf))
+
+	tbl.Write(sample{Name: "a", Value: 1e21, Avg: 2e6})
+	tbl.Write(sample{Name: "b", Value: 2.5e6, Avg: 3e6})
+	tbl.Write(sample{Name: "c", Value: 1e-7, Avg: 4e6})
+
+	if err := tbl.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "NAME  VALUE   AVG\n" +
+		"a     1e+21   2000000\n" +
+		"b     2500000 3000000\n" +
+		"c     1e-07   4000000\n" +
+		"----- ------- -------\n" +
+		"TOTAL 1e+21   3000000\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Flush() = %q, want %q", got, want)
+	}
+}
+
+func TestFooterPriority(t *testing.T) {
+	type sample struct {
+		X int `table:"X,max,avg,min,sum"`
+		Y int `table:"NODES"`
+	}
+
+	for range 10 {
+		var buf bytes.Buffer
+
+		tbl := New(WithWriter(&buf), WithFooter("NODES", Count), WithFooter("Y", Max))
+
+		tbl.Write(sample{X: 1, Y: 1})
+		tbl.Write(sample{X: 5, Y: 4})
+
+		if err := tbl.Flush(); err != nil {
+			t.Fatal(err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if got, want := lines[len(lines)-1], "6 4"; got != want {
+			t.Fatalf("footer = %q; want %q", got, want)
+		}
+	}
+}
+
+func TestFooterEnvelope(t *testing.T) {
+	type volume struct {
+		Name  string `table:"NAME,count"`
+		Bytes uint8  `table:"BYTES,sum"`
+	}
+
+	var buf bytes.Buffer
+
+	tbl := New(AsJSON(), WithWriter(&buf), WithEnvelope())
+
+	tbl.Write(volume{Name: "vol-1", Bytes: 200})
+	tbl.Write(volume{Name: "vol-2", Bytes: 100})
+	tbl.Write(volume{Bytes: 1})
+
+	if err := tbl.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), `"totals": {
+            "NAME": 2,
+            "BYTES": 301
+        }`) {
+		t.Errorf("expected totals in output, got: %s", buf.String())
+	}
+}
+
+func ExampleWithGroupBy() {
+	type host struct {
+		Zone    string `table:"ZONE"`
+		Cluster string `table:"CLUSTER"`
+		Host    string `table:"HOST"`
+		Cores   int    `table:"CORES,sum"`
+	}
+
+	var buf bytes.Buffer
+
+	t := New(WithWriter(&buf), WithGroupBy("ZONE", "Cluster"))
+
+	t.Write(host{Zone: "east", Cluster: "prod", Host: "compute-1", Cores: 8})
+	t.Write(host{Zone: "west", Cluster: "prod", Host: "compute-2", Cores: 16})
+	t.Write(host{Zone: "east", Cluster: "dev", Host: "compute-3", Cores: 4})
+	t.Write(host{Zone: "east", Cluster: "prod", Host: "compute-4", Cores: 8})
+	_ = t.Flush()
+
+	fmt.Print(buf.String())
+	// Output:
+	// HOST      CORES
+	// ZONE: east, CLUSTER: prod
+	// compute-1 8
+	// compute-4 8
+	// --------- -----
+	// SUBTOTAL  16
+	// ZONE: east, CLUSTER: dev
+	// compute-3 4
+	// --------- -----
+	// SUBTOTAL  4
+	// ZONE: west, CLUSTER: prod
+	// compute-2 16
+	// --------- -----
+	// SUBTOTAL  16
+	// --------- -----
+	// TOTAL     36
+}
+
+func TestGroupByAnnotations(t *testing.T) {
+	type host struct {
+		Zone string `table:"ZONE"`
+		Host string `table:"HOST"`
+	}
+
+	var buf bytes.Buffer
+
+	tbl := New(WithWriter(&buf), WithGroupBy("ZONE"))
+
+	tbl.Write(host{Zone: "east", Host: "a"})
+	tbl.Annotate("note")
+	tbl.Write(host{Zone: "west", Host: "b"})
+	tbl.Write(host{Zone: "east", Host: "c"})
+
+	if err := tbl.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	want := "HOST\nZONE: east\na\nc\nnote\nZONE: west\nb\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Flush() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGroupByMarkdownFooter(t *testing.T) {
+	type host struct {
+		Zone  string `table:"ZONE"`
+		Host  string `table:"HOST"`
+		Cores int    `table:"CORES,sum"`
+	}
+
+	var buf bytes.Buffer
+
+	tbl := New(AsMarkdown(), WithWriter(&buf), WithGroupBy("ZONE"))
+
+	tbl.Write(host{Zone: "east", Host: "a", Cores: 8})
+	tbl.Write(host{Zone: "west", Host: "b", Cores: 16})
+	tbl.Write(host{Zone: "east", Host: "c", Cores: 8})
+
+	if err := tbl.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	want := "| ZONE  | HOST | CORES |\n" +
+		"| ----- | ---- | ----: |\n" +
+		"| east  | a    |     8 |\n" +
+		"| east  | c    |     8 |\n" +
+		"| west  | b    |    16 |\n" +
+		"| TOTAL |      |    32 |\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Flush() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func ExampleWithSort() {
+	type node struct {
+		Zone string `table:"ZONE"`
+		Host string `table:"HOST"`
+		Rank int    `table:"RANK"`
+	}
+
+	var buf bytes.Buffer
+
+	t := New(WithWriter(&buf), WithSort("ZONE", "-RANK"))
+
+	t.Write(node{Zone: "west", Host: "compute-0-1", Rank: 1})
+	t.Write(node{Zone: "east", Host: "compute-0-9", Rank: 9})
+	t.Write(node{Zone: "east", Host: "compute-0-10", Rank: 10})
+	t.Write(node{Zone: "west", Host: "compute-0-2", Rank: 2})
+	_ = t.Flush()
+
+	fmt.Print(buf.String())
+	// Output:
+	// ZONE HOST         RANK
+	// east compute-0-10 10
+	// east compute-0-9  9
+	// west compute-0-2  2
+	// west compute-0-1  1
+}
+
+func TestSortTags(t *testing.T) {
+	type node struct {
+		Host    string        `table:"HOST,sort=2"`
+		Uptime  time.Duration `table:"UPTIME,sort=-1"`
+		Created time.Time     `table:"CREATED"`
+	}
+
+	var buf bytes.Buffer
+
+	tbl := New(AsCSV(), WithWriter(&buf), WithNaturalSort())
+
+	tbl.Write(node{Host: "compute-0-10", Uptime: time.Hour})
+	tbl.Write(node{Host: "compute-0-9", Uptime: time.Hour})
+	tbl.Write(node{Host: "compute-0-1", Uptime: 2 * time.Hour})
+
+	if err := tbl.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	var hosts []string
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n")[1:] {
+		host, _, _ := strings.Cut(line, ",")
+		hosts = append(hosts, host)
+	}
+
+	want := []string{"compute-0-1", "compute-0-9", "compute-0-10"}
+	if !reflect.DeepEqual(hosts, want) {
+		t.Errorf("sorted hosts = %v, want %v", hosts, want)
+	}
+}
+
+func TestNaturalCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"compute-0-9", "compute-0-10", -1},
+		{"compute-1-0", "compute-0-10", 1},
+		{"a01", "a1", -1},
+		{"a", "a1", -1},
+		{"b", "a1", 1},
+		{"x", "x", 0},
+	}
+
+	for _, tt := range tests {
+		if got := naturalCompare(tt.a, tt.b); got != tt.want {
+			t.Errorf("naturalCompare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func ExampleWithFilter() {
+	var buf bytes.Buffer
+
+	t := New(WithWriter(&buf), WithFilter(`Status == "running" && PORT >= 8081 || NAME =~ "^db-"`))
+
+	t.Write(server{Name: "web-1", Status: "running", Port: 8080})
+	t.Write(server{Name: "web-2", Status: "running", Port: 8081})
+	t.Write(server{Name: "web-3", Status: "stopped", Port: 8082})
+	t.Write(server{Name: "db-1", Status: "stopped", Port: 5432})
+	_ = t.Flush()
+
+	fmt.Print(buf.String())
+	// Output:
+	// NAME  STATUS  PORT
+	// web-2 running 8081
+	// db-1  stopped 5432
+}
+
+func TestFilter(t *testing.T) {
+	type job struct {
+		Name    string        `table:"NAME"`
+		Elapsed time.Duration `table:"ELAPSED"`
+		Started time.Time     `table:"STARTED"`
+		Done    bool          `table:"DONE"`
+		Load    float64       `table:"LOAD"`
+	}
+
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	jobs := []job{
+		{Name: "a", Elapsed: time.Minute, Started: start, Done: true, Load: 0.5},
+		{Name: "b", Elapsed: time.Hour, Started: start.AddDate(0, 0, 1), Load: 1.5},
+		{Name: "c b", Elapsed: 5 * time.Minute, Started: start.AddDate(0, 0, -1), Load: 2},
+	}
+
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{`ELAPSED > 5m`, "b"},
+		{`ELAPSED <= 5m && !DONE`, "c b"},
+		{`STARTED >= 2024-06-01`, "a,b"},
+		{`STARTED < "2024-06-01T12:00:00Z"`, "c b"},
+		{`DONE == true || LOAD > 1.5`, "a,c b"},
+		{`(NAME == b || NAME == "c b") && LOAD != 2`, "b"},
+		{`NAME !~ "b$"`, "a"},
+		{`MISSING == 1`, ""},
+		{`DONE`, "a"},
+		{`LOAD == fast`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			tbl := New(AsCSV(), WithWriter(&buf), WithFilter(tt.expr))
+			for _, j := range jobs {
+				tbl.Write(j)
+			}
+
+			if err := tbl.Flush(); err != nil {
+				t.Fatalf("Flush() error = %v", err)
+			}
+
+			var names []string
+
+			for i, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				if name, _, _ := strings.Cut(line, ","); i > 0 {
+					names = append(names, name)
+				}
+			}
+
+			if got := strings.Join(names, ","); got != tt.want {
+				t.Errorf("filtered names = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterErrors(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`PORT >=`,
+		`PORT >= 1 &&`,
+		`(PORT >= 1`,
+		`PORT >= 1)`,
+		`NAME == "web`,
+		`NAME =~ "["`,
+		`== 1`,
+	} {
+		var buf bytes.Buffer
+
+		tbl := New(WithWriter(&buf), WithFilter(expr))
+		tbl.Write(server{Name: "web-1", Status: "running", Port: 8080})
+
+		if err := tbl.Err(); !errors.Is(err, ErrFilter) {
+			t.Errorf("WithFilter(%q) Err() = %v, want ErrFilter", expr, err)
+		}
+
+		if err := tbl.Flush(); !errors.Is(err, ErrFilter) || buf.Len() > 0 {
+			t.Errorf("WithFilter(%q) Flush() = %v, wrote %q", expr, err, buf.String())
+		}
+	}
+}
+
+func ExampleWithColumns() {
+	var buf bytes.Buffer
+
+	t := New(WithWriter(&buf), WithColumns("HOST", "RANK", "Zone"))
+
+	t.Write(host{Zone: "east", Cluster: "prod", Host: "compute-0-0", Rank: 0})
+	t.Write(host{Zone: "west", Cluster: "prod", Host: "compute-0-1", Rank: 1})
+	_ = t.Flush()
+
+	fmt.Print(buf.String())
+	// Output:
+	// HOST        RANK ZONE
+	// compute-0-0 0    east
+	// compute-0-1 1    west
+}
+
+func TestWithoutColumns(t *testing.T) {
+	var buf bytes.Buffer
+
+	tbl := New(AsJSON(), WithWriter(&buf), WithoutColumns("CLUSTER", "Rank"), WithFilter("RANK > 0"))
+
+	tbl.Write(host{Zone: "east", Cluster: "prod", Host: "compute-0-0", Rank: 0})
+	tbl.Write(host{Zone: "west", Cluster: "prod", Host: "compute-0-1", Rank: 1})
+	tbl.Write("not a struct")
+
+	if err := tbl.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	var got []map[string]any
+
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	want := []map[string]any{{"ZONE": "west", "HOST": "compute-0-1"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Flush() = %v, want %v", got, want)
+	}
+}
+
+func ExampleWithWide() {
+	type pod struct {
+		Name   string `table:"NAME"`
+		Status string `table:"STATUS"`
+		Node   string `table:"NODE,wide"`
+		Image  string `table:"IMAGE,tier=detail"`
+	}
+
+	var buf bytes.Buffer
+
+	for _, opt := range []func(*Table){WithWide(), WithTier("wide", "detail")} {
+		t := New(WithWriter(&buf), opt)
+
+		t.Write(pod{Name: "web-1", Status: "running", Node: "node-1", Image: "nginx:1.27"})
+		_ = t.Flush()
+	}
+
+	fmt.Print(buf.String())
+	// Output:
+	// NAME  STATUS  NODE
+	// web-1 running node-1
+	// NAME  STATUS  NODE   IMAGE
+	// web-1 running node-1 nginx:1.27
+}
+
+func TestTierHidden(t *testing.T) {
+	type pod struct {
+		Name string `table:"NAME"`
+		Node string `table:"NODE,wide,omitempty"`
+	}
+
+	tests := []struct {
+		opts []func(*Table)
+		pod  pod
+		want string
+	}{
+		{nil, pod{Name: "a", Node: "n"}, "NAME\na\n"},
+		{[]func(*Table){WithWide()}, pod{Name: "a"}, "NAME\na\n"},
+		{[]func(*Table){WithWide()}, pod{Name: "a", Node: "n"}, "NAME NODE\na    n\n"},
+		{[]func(*Table){WithColumns("NODE")}, pod{Name: "a", Node: "n"}, "NODE\nn\n"},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+
+		tbl := New(append(tt.opts, WithWriter(&buf))...)
+		tbl.Write(tt.pod)
+		_ = tbl.Flush()
+
+		if got := buf.String(); got != tt.want {
+			t.Errorf("Flush() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestTierHiddenJSON(t *testing.T) {
+	type pod struct {
+		Name string `table:"NAME"`
+		Node string `table:"NODE,wide"`
+	}
+
+	tests := []struct {
+		opts []func(*Table)
+		want []map[string]any
+	}{
+		{nil, []map[string]any{{"NAME": "a"}}},
+		{[]func(*Table){WithWide()}, []map[string]any{{"NAME": "a", "NODE": "n"}}},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+
+		tbl := New(append(tt.opts, AsJSON(), WithWriter(&buf))...)
+		tbl.Write(pod{Name: "a", Node: "n"})
+
+		if err := tbl.Flush(); err != nil {
+			t.Fatal(err)
+		}
+
+		var got []map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Flush() = %v, want %v", got, tt.want)
+		}
+	}
+}
+
+type Location struct {
+	Zone string `table:"ZONE"`
+	Rack string `table:"RACK"`
+}
+
+type Resources struct {
+	Cores  int   `table:"CORES"`
+	Memory int64 `table:"MEMORY"`
+}
+
+func ExampleTable_Write_nested() {
+	type machine struct {
+		Location
+		Name   string `table:"NAME"`
+		Net    struct{ Addr, Gateway string }
+		Limits Resources `table:"LIMIT.,flatten"`
+		Usage  Resources `table:",inline"`
+		Secret string    `table:"-"`
+	}
+
+	var buf bytes.Buffer
+
+	t := New(WithWriter(&buf), WithColumnEncoding())
+
+	m := machine{Location: Location{Zone: "east", Rack: "r1"}, Name: "compute-0-0", Secret: "hunter2"}
+	m.Net.Addr, m.Net.Gateway = "10.0.0.2", "10.0.0.1"
+	m.Limits = Resources{Cores: 8, Memory: 32}
+	m.Usage = Resources{Cores: 2, Memory: 12}
+
+	t.Write(m)
+	t.FlushText()
+	_ = t.FlushCSV()
+
+	fmt.Print(buf.String())
+	// Output:
+	// ZONE RACK NAME        NET                 LIMIT.CORES LIMIT.MEMORY CORES MEMORY
+	// east r1   compute-0-0 {10.0.0.2 10.0.0.1} 8           32           2     12
+	// ZONE,RACK,NAME,NET,LIMIT.CORES,LIMIT.MEMORY,CORES,MEMORY
+	// east,r1,compute-0-0,{10.0.0.2 10.0.0.1},8,32,2,12
+}
+
+func TestEmbeddedFields(t *testing.T) {
+	type inner struct {
+		Name string
+		Port int
+	}
+
+	type outer struct {
+		*inner
+		Name string // hides inner.Name
+		*Location
+		Rack string `table:"SHELF"` // hides Location.Rack
+	}
+
+	var buf bytes.Buffer
+
+	tbl := New(AsJSON(), WithWriter(&buf), WithColumnEncoding(), WithFilter("ZONE != west"))
+
+	tbl.Write(outer{inner: &inner{Name: "hidden", Port: 80}, Name: "a", Rack: "r1"})
+	tbl.Write(outer{Name: "b", Location: &Location{Zone: "east", Rack: "hidden"}})
+	tbl.Write(outer{Name: "c", Location: &Location{Zone: "west"}})
+
+	if err := tbl.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	var got []map[string]any
+
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	want := []map[string]any{
+		{"NAME": "a", "PORT": 80.0, "ZONE": nil, "SHELF": "r1"},
+		{"NAME": "b", "PORT": nil, "ZONE": "east", "SHELF": ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Flush() = %v, want %v", got, want)
+	}
+}
+
+func TestEmbeddedUnexportedPointer(t *testing.T) {
+	type loc struct {
+		Zone string
+	}
+
+	type server struct {
+		*loc
+		X int
+	}
+
+	for _, opts := range [][]func(*Table){{AsJSON()}, {AsJSON(), WithColumnEncoding()}} {
+		var buf bytes.Buffer
+
+		tbl := New(append(opts, WithWriter(&buf))...)
+		tbl.Write(server{loc: &loc{Zone: "east"}, X: 3})
+
+		if err := tbl.Flush(); err != nil {
+			t.Fatal(err)
+		}
+
+		var got []map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(got) != 1 || got[0]["ZONE"] != "east" && got[0]["Zone"] != "east" {
+			t.Errorf("Flush() = %s, want the promoted Zone", buf.String())
+		}
+	}
+
+	var buf bytes.Buffer
+
+	tbl := New(WithWriter(&buf))
+	tbl.Write(server{loc: &loc{Zone: "east"}, X: 3})
+	_ = tbl.Flush()
+
+	if want := "ZONE X\neast 3\n"; buf.String() != want {
+		t.Errorf("Flush() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestEmbeddedCycle(t *testing.T) {
+	type Node struct {
+		*Node
+		Name string
+	}
+
+	var buf bytes.Buffer
+
+	tbl := New(WithWriter(&buf))
+
+	tbl.Write(Node{Name: "a"})
+	tbl.Write(Node{Node: &Node{Name: "x"}, Name: "b"})
+
+	if err := tbl.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "NODE      NAME\n--------- a\n{<nil> x} b\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Flush() = %q, want %q", got, want)
+	}
+}
+
+func ExampleTable_Write_pointers() {
+	type resource struct {
+		Name  string `table:"NAME"`
+		Owner *string
+		Size  *int `table:"SIZE,sum"`
+		Extra any  `table:"EXTRA,omitempty"`
+	}
+
+	owner, size := "alice", 10
+
+	var buf bytes.Buffer
+
+	t := New(WithWriter(&buf))
+
+	for _, r := range []*resource{
+		{Name: "vol-1", Owner: &owner, Size: &size},
+		{Name: "vol-2"},
+	} {
+		t.Write(r)
+	}
+
+	_ = t.Flush()
+
+	fmt.Print(buf.String())
+	// Output:
+	// NAME  OWNER SIZE
+	// vol-1 alice 10
+	// vol-2 ----- ----
+	// ----- ----- ----
+	// TOTAL       10
+}
+
+func TestWriteNil(t *testing.T) {
+	var (
+		buf bytes.Buffer
+		srv *server
+	)
+
+	tbl := New(WithWriter(&buf))
+
+	tbl.Write(nil)
+	tbl.Write(srv)
+	_ = tbl.Flush()
+
+	for _, want := range []string{"skipping non-struct type: <nil>", "skipping nil *table.server", "not a struct"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Flush() = %q, want %q", buf.String(), want)
+		}
+	}
+}
+
+type version struct {
+	Major, Minor int
+}
+
+func (v version) String() string {
+	return fmt.Sprintf("v%d.%d", v.Major, v.Minor)
+}
+
+func TestWriteNilStringer(t *testing.T) {
+	var buf bytes.Buffer
+
+	tbl := New(WithWriter(&buf))
+
+	tbl.Write((*version)(nil))
+	tbl.Write(version{Major: 1, Minor: 2})
+	_ = tbl.Flush()
+
+	for _, want := range []string{"*table.version <nil>", "skipping nil *table.version", "1     2"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Flush() = %q, want %q", buf.String(), want)
+		}
+	}
+}
+
+func ExampleTable_Write_lists() {
+	type instance struct {
+		Name   string            `table:"NAME"`
+		Tags   []string          `table:"TAGS,limit=2,join=; "`
+		Labels map[string]string `table:"LABELS"`
+		Disks  []string          `table:"DISKS,explode"`
+	}
+
+	var buf bytes.Buffer
+
+	t := New(WithWriter(&buf))
+
+	t.Write(instance{
+		Name:   "web-1",
+		Tags:   []string{"prod", "web", "east"},
+		Labels: map[string]string{"team": "ops", "app": "nginx"},
+		Disks:  []string{"sda", "sdb"},
+	})
+	t.Write(instance{Name: "web-2", Tags: []string{"dev"}})
+	_ = t.Flush()
+
+	fmt.Print(buf.String())
+	// Output:
+	// NAME  TAGS              LABELS              DISKS
+	// web-1 prod; web +1 more app=nginx, team=ops sda
+	//                                             sdb
+	// web-2 dev               ------------------- -----
+}
+
+func TestUnexportedList(t *testing.T) {
+	type instance struct {
+		Name string   `table:"NAME"`
+		tags []string `table:"TAGS"`
+	}
+
+	var buf bytes.Buffer
+
+	tbl := New(WithWriter(&buf))
+	tbl.Write(instance{Name: "web-1", tags: []string{"x", "y", "z"}})
+
+	if err := tbl.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "NAME  TAGS\nweb-1 ----\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Flush() = %q, want %q", got, want)
+	}
+}
+
+func TestParseTagOptions(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want tagOptions
+	}{
+		{"omitempty,align=right", tagOptions{"omitempty": "", "align": "right"}},
+		{" sum , limit=3 ", tagOptions{"sum": "", "limit": "3"}},
+		{"limit=3,join=, ", tagOptions{"limit": "3", "join": ", "}},
+		{"join=|", tagOptions{"join": "|"}},
+	}
+
+	for _, tt := range tests {
+		if got := parseTagOptions(tt.tag); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseTagOptions(%q) = %v, want %v", tt.tag, got, tt.want)
+		}
+	}
+}
+
+type level int
+
+func (l level) MarshalText() ([]byte, error) {
+	return []byte([]string{"low", "high"}[l]), nil
+}
+
+func ExampleTable_Write_wellKnownTypes() {
+	type event struct {
+		Created time.Time      `table:"CREATED,time=datetime,tz=UTC"`
+		Elapsed time.Duration  `table:"ELAPSED,round=1s"`
+		Owner   sql.NullString `table:"OWNER"`
+		Retries sql.NullInt64  `table:"RETRIES"`
+		Level   level          `table:"LEVEL"`
+		Err     error          `table:"ERROR"`
+	}
+
+	created := time.Date(2024, 6, 1, 14, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	var buf bytes.Buffer
+
+	t := New(WithWriter(&buf))
+
+	t.Write(event{
+		Created: created,
+		Elapsed: 1500*time.Millisecond + 300*time.Microsecond,
+		Owner:   sql.NullString{String: "alice", Valid: true},
+		Retries: sql.NullInt64{Int64: 3},
+		Level:   1,
+		Err:     io.ErrUnexpectedEOF,
+	})
+	_ = t.Flush()
+
+	fmt.Print(buf.String())
+	// Output:
+	// CREATED             ELAPSED OWNER RETRIES LEVEL ERROR
+	// 2024-06-01 12:30:00 2s      alice ------- high  unexpected EOF
+}
+
+func TestRelativeTime(t *testing.T) {
+	current := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return current }
+
+	defer func() { now = time.Now }()
+
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "now"},
+		{5 * time.Minute, "5m ago"},
+		{-90 * time.Second, "in 1m"},
+		{30 * time.Hour, "30h ago"},
+		{72 * time.Hour, "3d ago"},
+	}
+
+	for _, tt := range tests {
+		if got := relativeTime(current.Add(-tt.d)); got != tt.want {
+			t.Errorf("relativeTime(now - %v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestMonotonicTime(t *testing.T) {
+	type job struct {
+		Started time.Time
+	}
+
+	var buf bytes.Buffer
+
+	tbl := New(WithWriter(&buf))
+	tbl.Write(job{Started: time.Now()})
+	tbl.Write(job{})
+	_ = tbl.Flush()
+
+	if strings.Contains(buf.String(), "m=") || strings.Contains(buf.String(), "0001") {
+		t.Errorf("Flush() = %q, want RFC 3339 times", buf.String())
+	}
+}
+
+type celsius float64
+
+func ExampleRegisterFormatter() {
+	RegisterFormatter(func(c celsius) string {
+		return fmt.Sprintf("%.1f°C", float64(c))
+	})
+
+	type sensor struct {
+		Name    string      `table:"NAME"`
+		Addr    netip.Addr  `table:"ADDR,omitempty"`
+		Temp    celsius     `table:"TEMP"`
+		History []celsius   `table:"HISTORY"`
+		Peer    *netip.Addr `table:"PEER"`
+	}
+
+	var buf bytes.Buffer
+
+	t := New(WithWriter(&buf), WithFormatter(func(a netip.Addr) string {
+		if !a.IsValid() {
+			return ""
+		}
+
+		return "ip:" + a.String()
+	}))
+
+	peer := netip.MustParseAddr("10.0.0.2")
+
+	t.Write(sensor{Name: "s1", Temp: 21.55, History: []celsius{20, 21.5}, Peer: &peer})
+	t.Write(sensor{Name: "s2", Addr: netip.Addr{}, Temp: 19})
+	_ = t.Flush()
+
+	fmt.Print(buf.String())
+	// Output:
+	// NAME TEMP   HISTORY        PEER
+	// s1   21.6°C 20.0°C, 21.5°C ip:10.0.0.2
+	// s2   19.0°C -------------- -----------
+}
+
+func TestFormattedValues(t *testing.T) {
+	type reading struct {
+		Temp celsius `table:"TEMP"`
+		Raw  float64 `table:"RAW"`
+	}
+
+	var buf bytes.Buffer
+
+	tbl := New(AsJSON(), WithWriter(&buf), WithFormattedValues(), WithFormatter(func(c celsius) string {
+		return fmt.Sprintf("%gC", float64(c))
+	}))
+	tbl.Write(reading{Temp: 20.5, Raw: 20.5})
+
+	if err := tbl.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	var got []map[string]any
+
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	want := []map[string]any{{"TEMP": "20.5C", "RAW": 20.5}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Flush() = %v, want %v", got, want)
+	}
+}
+
+func ExampleTable_Write_units() {
+	type volume struct {
+		Name  string  `table:"NAME"`
+		Size  int64   `table:"SIZE,bytes,sum"`
+		Used  int64   `table:"USED,bytes,scale=column"`
+		Reads uint64  `table:"READS,count=si"`
+		Full  float64 `table:"FULL,percent"`
+	}
+
+	var buf bytes.Buffer
+
+	t := New(WithWriter(&buf), WithNumericAlignment())
+
+	t.Write(volume{Name: "vol-1", Size: 512, Used: 1 << 29, Reads: 999, Full: 0.5})
+	t.Write(volume{Name: "vol-2", Size: 1 << 30, Used: 3 << 30, Reads: 1_234_567, Full: 0.123})
+	_ = t.Flush()
+
+	fmt.Print(buf.String())
+	// Output:
+	// NAME     SIZE  USED READS  FULL
+	//               (GiB)
+	// vol-1   512 B   0.5   999 50.0%
+	// vol-2 1.0 GiB   3.0  1.2M 12.3%
+	// ----- ------- ----- ----- -----
+	// TOTAL 1.0 GiB
+}
+
+func TestUnitsEncoding(t *testing.T) {
+	type volume struct {
+		Used int64 `table:"USED,bytes,scale=column"`
+	}
+
+	var buf bytes.Buffer
+
+	tbl := New(AsJSON(), WithWriter(&buf), WithColumnEncoding())
+	tbl.Write(volume{Used: 3 << 30})
+
+	if err := tbl.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	if got := buf.String(); !strings.Contains(got, `"USED": 3221225472`) {
+		t.Errorf("Flush() = %s, want the raw number of bytes", got)
+	}
+}
+
+func ExampleWithLocale() {
+	type load struct {
+		Host  string  `table:"HOST"`
+		Bytes int64   `table:"BYTES,sum"`
+		Load  float64 `table:"LOAD,prec=2"`
+	}
+
+	var buf bytes.Buffer
+
+	for _, tag := range []string{"en-US", "de_DE"} {
+		t := New(WithWriter(&buf), WithLocale(tag), WithNumericAlignment())
+
+		t.Write(load{Host: "web-1", Bytes: 1234567, Load: 0.5})
+		t.Write(load{Host: "web-2", Bytes: -42, Load: 12.345})
+		_ = t.Flush()
+	}
+
+	fmt.Print(buf.String())
+	// Output:
+	// HOST      BYTES  LOAD
+	// web-1 1,234,567  0.50
+	// web-2       -42 12.35
+	// ----- --------- -----
+	// TOTAL 1,234,525
+	// HOST      BYTES  LOAD
+	// web-1 1.234.567  0,50
+	// web-2       -42 12,35
+	// ----- --------- -----
+	// TOTAL 1.234.525
+}
+
+func TestLocaleNumber(t *testing.T) {
+	tests := []struct {
+		l    *locale
+		s    string
+		want string
+	}{
+		{nil, "1234.5", "1234.5"},
+		{&pointLocale, "123", "123"},
+		{&pointLocale, "-1234567.125", "-1,234,567.125"},
+		{&commaLocale, "1000", "1.000"},
+		{&spaceLocale, "12345.6", "12\u00a0345,6"},
+		{&apostropheLocale, "1000000", "1’000’000"},
+		{&pointLocale, "+Inf", "+Inf"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.l.number(tt.s); got != tt.want {
+			t.Errorf("number(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+
+	if err := New(WithLocale("xx")).Err(); !errors.Is(err, ErrUnknownLocale) {
+		t.Errorf("WithLocale(%q) Err() = %v, want ErrUnknownLocale", "xx", err)
+	}
+}
+
+func TestLocaleUnexported(t *testing.T) {
+	type disk struct {
+		Name  string `table:"NAME"`
+		size  int    `table:"SIZE"`
+		Count int    `table:"COUNT"`
+	}
+
+	var buf bytes.Buffer
+
+	tbl := New(WithWriter(&buf), WithLocale("en"))
+	tbl.Write(disk{Name: "sda", size: 1234, Count: 5678})
+
+	if err := tbl.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "NAME SIZE COUNT\nsda  ---- 5,678\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Flush() = %q, want %q", got, want)
+	}
+}
+
+func TestCellStyle(t *testing.T) {
+	type service struct {
+		Status string  `table:"STATUS,color=running:green|stopped:bold+red|*:yellow"`
+		Usage  float64 `table:"USAGE,color=>90:red|>=70:yellow|bogus:pink"`
+		Name   string  `table:"NAME"`
+	}
+
+	tbl := New(WithCellStyle(func(col Column, v reflect.Value) []sgr.Param {
+		if col.Name == "Name" && v.String() == "db" {
+			return color.Cyan
+		}
+
+		return nil
+	}))
+
+	columns := tbl.processHeader(reflect.TypeFor[service]())
+
+	tests := []struct {
+		column int
+		value  any
+		want   []sgr.Param
+	}{
+		{0, "running", color.Green},
+		{0, "stopped", []sgr.Param{sgr.Bold, sgr.Red.FG()}},
+		{0, "unknown", color.Yellow},
+		{1, 95.0, color.Red},
+		{1, 70.0, color.Yellow},
+		{1, 50.0, nil},
+		{2, "db", color.Cyan},
+		{2, "web", nil},
+	}
+
+	for _, tt := range tests {
+		v := reflect.ValueOf(tt.value)
+		cell := Cell{Text: columns[tt.column].text(v), Value: v}
+
+		if got := tbl.style(columns[tt.column], cell); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("style(%s, %v) = %v, want %v", columns[tt.column].Name, tt.value, got, tt.want)
+		}
+	}
+}
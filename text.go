@@ -2,24 +2,16 @@ package table
 
 import (
 	"fmt"
+	"io"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"endobit.io/table/sgr"
 )
 
-type cell struct {
-	Text  string
-	Value reflect.Value
-}
-
-type columnInfo struct {
-	Labels    []string
-	Width     int
-	OmitEmpty bool
-	IsZero    bool
-}
-
 type wrapper interface {
 	Wrap() sgr.Wrapped
 }
@@ -28,15 +20,47 @@ type wrapper interface {
 // styled text. If the io.Writer is not a terminal no ANSI styles will be
 // applied.
 func (t *Table) FlushText() {
+	if t.err != nil {
+		return
+	}
+
+	_ = t.encodeText(t.writer, t.sections())
+}
+
+func (t *Table) encodeText(w io.Writer, sections []Section) error {
+	width := t.width(w)
+	vertical := make([]bool, len(sections))
+
+	for i, s := range sections {
+		if vertical[i] = t.isVertical(s, width); !vertical[i] {
+			fit(s.Columns, width-t.frameWidth(), t.separatorWidth())
+		}
+	}
+
+	for i, s := range sections {
+		if i > 0 && t.sectionRules {
+			t.writeRule(w, s.Columns, ruleMiddle)
+		}
+
+		if vertical[i] {
+			t.flushVertical(w, s)
+		} else {
+			t.flush(w, s)
+		}
+	}
+
+	return nil
+}
+
+// sections splits the rows into sections at each struct type change. This is
+// the first pass through the table to determine the column widths and cell
+// contents. ANSI formatting is not part of this pass.
+func (t *Table) sections() []Section {
 	var (
 		prevType reflect.Type
-		columns  []columnInfo
-		cells    [][]cell
+		sections []Section
 	)
 
-	// This is the first pass through the table to determine the column widths
-	// and cell contents. ANSI formatting is not part of this pass.
-
 	for i := range t.rows {
 		val := reflect.ValueOf(t.rows[i])
 		currType := reflect.TypeOf(t.rows[i])
@@ -44,190 +68,630 @@ func (t *Table) FlushText() {
 		if currType != prevType { // start a new table
 			prevType = currType
 
-			if columns != nil { // flush and reset for next table
-				t.flush(columns, cells)
-				cells = nil
+			sections = append(sections, Section{
+				Type:    currType,
+				Columns: t.processHeader(currType),
+			})
+		}
+
+		s := &sections[len(sections)-1]
+
+		for _, a := range t.annotations {
+			if a.Index == i {
+				s.Annotations = append(s.Annotations, Annotation{Index: len(s.Cells), Text: a.Text})
 			}
+		}
+
+		cells := t.processRow(val, s.Columns)
+
+		// Annotations of filtered rows stay with the next row.
 
-			columns = t.processHeader(currType)
+		if t.filter != nil && currType != reflect.TypeFor[writeError]() && !t.filter.match(s.Columns, cells) {
+			continue
 		}
 
-		numFields := val.NumField()
-		fields := make([]cell, numFields)
+		t.growColumns(s.Columns, cells)
 
-		for j := range numFields {
-			value := val.Field(j)
-			cell := cell{
-				Text:  valueAsString(value), // cache it
-				Value: value,
-			}
+		s.Rows = append(s.Rows, t.rows[i])
+		s.Cells = append(s.Cells, cells)
+	}
 
-			length := len(cell.Text)
+	if len(sections) == 0 {
+		return nil
+	}
 
-			// If the value is a wrapper, use its Wrap() method to get the text
-			// and its length.
-			if value.CanInterface() {
-				if a, ok := value.Interface().(wrapper); ok {
-					w := a.Wrap()
+	// trailing annotations are printed after the last row
+	s := &sections[len(sections)-1]
 
-					length = len(w.Text)
-					if t.noColor {
-						cell.Text = w.Text
-					}
-				}
-			}
+	for _, a := range t.annotations {
+		if a.Index >= len(t.rows) {
+			s.Annotations = append(s.Annotations, Annotation{Index: len(s.Cells), Text: a.Text})
+		}
+	}
 
-			if length > columns[j].Width {
-				columns[j].Width = length
-			}
+	for i := range sections {
+		t.finish(&sections[i])
+	}
+
+	if t.filter != nil { // drop the tables with every row filtered out
+		sections = slices.DeleteFunc(sections, func(s Section) bool {
+			return len(s.Cells) == 0
+		})
+	}
+
+	return sections
+}
+
+// finish arranges the rows of s and computes the column properties that
+// depend on all of the cells.
+func (t *Table) finish(s *Section) {
+	t.sort(s)
+	t.group(s)
+	t.selectColumns(s)
+
+	markEmptyColumns(s.Columns, s.Cells)
+
+	for i := range s.Groups {
+		g := &s.Groups[i]
+		g.Footer = footer(s.Columns, s.Cells[g.Start:g.End], subtotalLabel)
+	}
+
+	s.Footer = footer(s.Columns, s.Cells, footerLabel)
+
+	scaleColumns(s)
+
+	// footers are aligned with the rows
+
+	rows := slices.Clip(s.Cells)
+
+	for _, g := range s.Groups {
+		if g.Footer != nil {
+			rows = append(rows, g.Footer)
+		}
+	}
+
+	if s.Footer != nil {
+		rows = append(rows, s.Footer)
+	}
+
+	alignDecimals(s.Columns, rows)
+}
+
+// processRow converts the fields of val into cells.
+func (t *Table) processRow(val reflect.Value, columns []Column) []Cell {
+	fields := make([]Cell, len(columns))
+
+	for j, c := range columns {
+		value, err := val.FieldByIndexErr(c.index)
+		if err != nil { // nil embedded struct pointer
+			continue
+		}
+
+		value = indirect(value)
 
-			fields[j] = cell
+		cell := Cell{
+			Text:  c.text(value), // cache it
+			Value: value,
 		}
 
-		cells = append(cells, fields)
+		// If the value is a wrapper, use the text of its Wrap() method.
+		if a, ok := cell.wrapper(); ok && t.noColor {
+			cell.Text = a.Wrap().Text
+		}
+
+		fields[j] = cell
 	}
 
-	t.flush(columns, cells)
+	return fields
 }
 
-func (t *Table) flush(info []columnInfo, rows [][]cell) {
-	t.flushHeader(info, rows)
+// growColumns grows the column widths to fit the cells of a row.
+func (*Table) growColumns(columns []Column, cells []Cell) {
+	for j, cell := range cells {
+		length := maxStringLength(strings.Split(cell.Text, "\n"))
+
+		// A wrapper's Wrap() text is printed in place of the cell text.
+		if a, ok := cell.wrapper(); ok {
+			length = displayWidth(a.Wrap().Text)
+		}
+
+		columns[j].Width = max(columns[j].Width, length)
+	}
+}
 
-	annotations := t.annotations
+// line is one line of a cell as printed: the styled text and the padding on
+// either side of it.
+type line struct {
+	left  string
+	text  string
+	right string
+}
+
+func (t *Table) flush(w io.Writer, s Section) {
+	info, rows, annotations := s.Columns, s.Cells, s.Annotations
+
+	t.flushHeader(w, info)
 
 	// This pass applies ANSI styles and prints the table rows.
 
+	groups := s.Groups
+	start := 0 // row parity, rules and repeats restart with each group
+
 	for i := range rows {
-		if len(annotations) > 0 && annotations[0].index == i {
-			fmt.Fprintln(t.writer, sgr.Wrap(t.colors.Annotation, annotations[0].text))
+		for len(annotations) > 0 && annotations[0].Index == i {
+			t.writeSpan(w, info, annotations[0].Text, t.colors.Annotation)
 			annotations = annotations[1:] // remove the annotation
 		}
 
+		if len(groups) > 0 && groups[0].Start == i {
+			if i > 0 && t.border != nil && groups[0].Footer == nil {
+				t.writeRule(w, info, ruleMiddle)
+			}
+
+			t.writeSpan(w, info, strings.Join(groups[0].Labels, ", "), t.colors.Group)
+			start = i
+		}
+
+		n := i - start
+
+		if n > 0 && t.rowRules > 0 && n%t.rowRules == 0 {
+			t.writeRule(w, info, ruleMiddle)
+		}
+
 		var repeats []bool
 
-		if i == 0 {
+		if n == 0 {
 			repeats = make([]bool, len(rows[i]))
 		} else {
 			repeats = findRepeats(rows[i-1], rows[i])
 		}
 
-		for j := range rows[i] {
-			if info[j].IsZero { // skip empty columns TODO: make this configurable
-				continue
-			}
+		rowColor := t.colors.EvenRow
+		if n%2 != 0 {
+			rowColor = t.colors.OddRow
+		}
+
+		// Wrapped cells span multiple lines, the tallest cell determines the
+		// height of the row.
 
-			rowColor := t.colors.EvenRow
-			if i%2 != 0 {
-				rowColor = t.colors.OddRow
+		lines := make([][]line, len(rows[i]))
+		height := 1
+
+		for j := range rows[i] {
+			if info[j].visible() { // skip empty columns TODO: make this configurable
+				lines[j] = t.cellLines(info[j], rows[i][j], repeats[j])
+				height = max(height, len(lines[j]))
 			}
+		}
 
-			cell := rows[i][j]
-			text := cell.Text
+		for k := range height {
+			cells := make([]*line, len(lines))
 
-			if !t.noColor && cell.Value.CanInterface() {
-				if a, ok := cell.Value.Interface().(wrapper); ok {
-					text = a.Wrap().String()
+			for j := range lines {
+				if k < len(lines[j]) {
+					cells[j] = &lines[j][k]
 				}
 			}
 
-			padding := strings.Repeat(" ", info[j].Width-len(cell.Text))
+			t.writeLine(w, info, cells, rowColor)
+		}
 
-			switch {
-			case cell.Text == "":
-				text = sgr.Wrap(t.colors.Empty, strings.Repeat("-", info[j].Width)).String()
-				padding = ""
-			case repeats[j]:
-				text = sgr.Wrap(t.colors.Repeat, text).String()
+		if len(groups) > 0 && groups[0].End == i+1 {
+			if groups[0].Footer != nil {
+				t.flushFooter(w, info, groups[0].Footer)
 			}
 
-			// Skip padding for the last column
-			if j == len(rows[i])-1 {
-				fmt.Fprint(t.writer, sgr.Wrap(rowColor, text))
-			} else {
-				fmt.Fprint(t.writer, sgr.Wrap(rowColor, text, padding), " ")
+			groups = groups[1:]
+		}
+	}
+
+	for _, a := range annotations {
+		t.writeSpan(w, info, a.Text, t.colors.Annotation)
+	}
+
+	if s.Footer != nil {
+		t.flushFooter(w, info, s.Footer)
+	}
+
+	if t.border != nil && t.border.Outer {
+		t.writeRule(w, info, ruleBottom)
+	}
+}
+
+// writeLine writes one line of the table. The cells have an entry for each
+// column, nil entries are blank.
+func (t *Table) writeLine(w io.Writer, info []Column, cells []*line, color []sgr.Param) {
+	separator, outer := " ", false
+
+	if b := t.border; b != nil {
+		separator = " " + b.Vertical + " "
+		outer = b.Outer
+	}
+
+	if outer {
+		fmt.Fprint(w, t.border.Vertical, " ")
+	}
+
+	// Without an outer border, padding is held back until a later column has
+	// text, so lines do not end with spaces.
+	var (
+		padding strings.Builder
+		first   = true
+	)
+
+	for j := range info {
+		if !info[j].visible() {
+			continue
+		}
+
+		if !first {
+			padding.WriteString(separator)
+		}
+
+		first = false
+
+		l := cells[j]
+		if l == nil {
+			fmt.Fprint(&padding, sgr.Wrap(color, strings.Repeat(" ", info[j].Width)))
+
+			continue
+		}
+
+		fmt.Fprint(w, padding.String(), sgr.Wrap(color, l.left, l.text))
+		padding.Reset()
+
+		if l.right != "" {
+			fmt.Fprint(&padding, sgr.Wrap(color, l.right))
+		}
+	}
+
+	if outer {
+		fmt.Fprint(w, padding.String(), " ", t.border.Vertical)
+	}
+
+	fmt.Fprintln(w)
+}
+
+// writeSpan writes a line of text, such as an annotation or a group header, in
+// the given color. Inside an outer border the line spans all the columns.
+func (t *Table) writeSpan(w io.Writer, info []Column, text string, color []sgr.Param) {
+	if t.border == nil || !t.border.Outer {
+		fmt.Fprintln(w, sgr.Wrap(color, text))
+
+		return
+	}
+
+	padding := max(tableWidth(info, false, t.separatorWidth())-displayWidth(text), 0)
+
+	fmt.Fprintln(w, t.border.Vertical, sgr.Wrap(color, text, strings.Repeat(" ", padding)),
+		t.border.Vertical)
+}
+
+// cellLines returns the styled lines of the cell for the column c. Text wider
+// than the column is truncated or wrapped.
+func (t *Table) cellLines(c Column, cell Cell, repeat bool) []line {
+	if cell.Text == "" {
+		return []line{{text: sgr.Wrap(t.colors.Empty, strings.Repeat("-", c.Width)).String()}}
+	}
+
+	var texts []string
+
+	for text := range strings.SplitSeq(cell.Text, "\n") { // exploded lists have a line per element
+		switch {
+		case displayWidth(text) <= c.Width, c.overflow == overflowNone:
+			texts = append(texts, text)
+		case c.overflow == overflowWrap:
+			texts = append(texts, wrapText(text, c.Width)...)
+		default:
+			texts = append(texts, truncate(text, c.Width, c.overflow))
+		}
+	}
+
+	lines := make([]line, len(texts))
+
+	for i, text := range texts {
+		var l line
+
+		l.left, l.right = c.padding(text)
+		l.text = text
+
+		// Cell styles apply to every line, but only text that is printed
+		// whole can use the wrapper styles.
+		if style := t.style(c, cell); style != nil && !t.noColor {
+			l.text = sgr.Wrap(style, text).String()
+		} else if text == cell.Text && !t.noColor {
+			if a, ok := cell.wrapper(); ok {
+				l.text = a.Wrap().String()
 			}
 		}
 
-		fmt.Fprintln(t.writer)
+		if repeat {
+			l.text = sgr.Wrap(t.colors.Repeat, l.text).String()
+		}
+
+		lines[i] = l
 	}
+
+	return lines
 }
 
-func (t *Table) flushHeader(info []columnInfo, rows [][]cell) {
+func (t *Table) flushHeader(w io.Writer, info []Column) {
 	var numLines int
 
 	// header can have multiple lines (useful for specifying units)
 	// column with the most lines determines the size of the header
 	for _, c := range info {
-		if len(c.Labels) > numLines {
+		if c.visible() && len(c.Labels) > numLines {
 			numLines = len(c.Labels)
 		}
 	}
 
+	if t.border != nil && t.border.Outer {
+		t.writeRule(w, info, ruleTop)
+	}
+
 	for i := range numLines {
-		if i > 0 {
-			fmt.Fprintln(t.writer)
+		cells := make([]*line, len(info))
+
+		for j := range info {
+			if i < len(info[j].Labels) {
+				label := info[j].Labels[i]
+				left, right := info[j].headerPadding(label)
+
+				cells[j] = &line{left: left, text: label, right: right}
+			}
 		}
 
-		for j := range info { // header
-			var label string
+		t.writeLine(w, info, cells, t.colors.Header)
+	}
 
-			if info[j].OmitEmpty && isColumnZero(j, rows) {
-				info[j].IsZero = true
+	if t.border != nil {
+		t.writeRule(w, info, ruleMiddle)
+	}
+}
 
-				continue
-			}
+// flushFooter writes the footer under a rule.
+func (t *Table) flushFooter(w io.Writer, info []Column, footer []Cell) {
+	t.writeRule(w, info, ruleMiddle)
 
-			if i < len(info[j].Labels) {
-				label = info[j].Labels[i]
-			}
+	cells := make([]*line, len(info))
 
-			fmt.Fprint(t.writer, sgr.Wrapf(t.colors.Header, "%-*s", info[j].Width, label))
+	for j := range footer {
+		if text := footer[j].Text; text != "" {
+			left, right := info[j].padding(text)
 
-			if j != len(info)-1 {
-				fmt.Fprint(t.writer, " ")
-			}
+			cells[j] = &line{left: left, text: text, right: right}
 		}
 	}
 
-	fmt.Fprintln(t.writer)
+	t.writeLine(w, info, cells, t.colors.Footer)
 }
 
-func (t *Table) processHeader(header reflect.Type) []columnInfo {
-	numFields := header.NumField()
+// processHeader builds the columns of a struct type. The fields of embedded
+// structs are promoted to columns, following the encoding/json rules, and the
+// fields of nested structs tagged "inline" or "flatten" are expanded.
+func (t *Table) processHeader(header reflect.Type) []Column {
+	columns := t.structColumns(header, fieldPath{exported: true})
 
-	columns := make([]columnInfo, numFields)
+	// Like encoding/json, a promoted field is hidden by a shallower field of
+	// the same name, and fields at the same depth hide each other.
 
-	for i := range numFields {
-		field := header.Field(i)
-		label := t.fieldToLabel(field.Name)
+	return slices.DeleteFunc(slices.Clone(columns), func(c Column) bool {
+		for _, o := range columns {
+			if o.Name == c.Name && !slices.Equal(o.index, c.index) && len(o.index) <= len(c.index) {
+				return true
+			}
+		}
 
-		columns[i] = columnInfo{
-			Labels: []string{label},
-			Width:  len(label),
+		return false
+	})
+}
+
+// fieldPath is the location of a nested struct.
+type fieldPath struct {
+	index    []int          // index of the struct field for reflect.Value.FieldByIndex
+	name     string         // name prefix, "Net." for a field Net
+	label    string         // label prefix, "NET." for a field Net
+	exported bool           // false if the fields can't be read with Interface()
+	visited  []reflect.Type // struct types being expanded, to stop cycles
+}
+
+func (t *Table) structColumns(typ reflect.Type, path fieldPath) []Column {
+	var columns []Column
+
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+
+		tag := field.Tag.Get("table")
+		if tag == "-" {
+			continue
 		}
 
-		if tag := field.Tag.Get("table"); tag != "" {
-			// Parse tag: "LABEL,omitempty" -> label="LABEL", omitEmpty=true
-			label, options, _ := strings.Cut(tag, ",")
+		label, options, _ := strings.Cut(tag, ",")
+		opts := parseTagOptions(options)
+
+		ft := field.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
 
-			if label != "" {
-				labels := strings.Split(label, "\n")
-				if labels[0] == "" { // use the default label if empty
-					labels[0] = columns[i].Labels[0]
+		nested := fieldPath{
+			index:    append(slices.Clip(path.index), i),
+			name:     path.name,
+			label:    path.label,
+			exported: path.exported && (field.IsExported() || field.Anonymous && ft.Kind() == reflect.Struct),
+			visited:  append(slices.Clip(path.visited), typ),
+		}
+
+		// A struct that contains itself is a single column, like any
+		// other struct.
+
+		if ft.Kind() == reflect.Struct && !slices.Contains(nested.visited, ft) {
+			switch {
+			case opts.has("inline"),
+				field.Anonymous && label == "" && !opts.has("flatten") && hasExportedFields(ft):
+				columns = append(columns, t.structColumns(ft, nested)...)
+
+				continue
+			case opts.has("flatten"):
+				if label == "" {
+					label = t.fieldToLabel(field.Name)
 				}
 
-				columns[i].Labels = labels
-				columns[i].Width = maxStringLength(labels)
-			}
+				nested.name += field.Name + "."
+				nested.label += strings.TrimSuffix(label, ".") + "."
+				columns = append(columns, t.structColumns(ft, nested)...)
 
-			columns[i].OmitEmpty = strings.Contains(options, "omitempty")
+				continue
+			}
 		}
+
+		nested.exported = path.exported && field.IsExported()
+		columns = append(columns, t.fieldColumn(field, nested, label, opts))
 	}
 
 	return columns
 }
 
-func findRepeats(top, bottom []cell) []bool {
+// fieldColumn returns the column for a struct field with the label and options
+// from its "table" struct tag.
+func (t *Table) fieldColumn(field reflect.StructField, path fieldPath, label string, opts tagOptions) Column {
+	labels := []string{t.fieldToLabel(field.Name)}
+
+	if label != "" {
+		labels = strings.Split(label, "\n")
+		if labels[0] == "" { // use the default label if empty
+			labels[0] = t.fieldToLabel(field.Name)
+		}
+	}
+
+	labels[0] = path.label + labels[0]
+
+	kind := field.Type.Kind()
+	for typ := field.Type; kind == reflect.Pointer; kind = typ.Kind() { // the kind of the value shown
+		typ = typ.Elem()
+	}
+
+	c := Column{
+		Name:        path.name + field.Name,
+		Labels:      labels,
+		Kind:        kind,
+		Width:       maxStringLength(labels),
+		OmitEmpty:   opts.has("omitempty"),
+		Align:       parseAlignment(opts["align"]),
+		exported:    path.exported,
+		index:       path.index,
+		overflow:    parseOverflow(opts),
+		tier:        opts["tier"],
+		separator:   defaultJoin,
+		formatter:   t.formatter,
+		locale:      t.locale,
+		explode:     opts.has("explode"),
+		units:       parseUnits(opts),
+		scaleColumn: opts["scale"] == "column",
+	}
+
+	for _, a := range aggregates {
+		if value, ok := opts[a.name]; ok && value == "" { // not "count=si"
+			c.Aggregate = a.fn
+
+			break
+		}
+	}
+
+	c.priority, _ = strconv.Atoi(opts["priority"])
+	c.sort, _ = strconv.Atoi(opts["sort"])
+	c.limit, _ = strconv.Atoi(opts["limit"])
+
+	if prec, err := strconv.Atoi(opts["prec"]); err == nil {
+		c.precision = prec
+	} else {
+		c.precision = -1 // the shortest text
+	}
+
+	c.round, _ = time.ParseDuration(opts["round"])
+
+	if layout, ok := timeLayouts[opts["time"]]; ok {
+		c.layout = layout
+	} else {
+		c.layout = opts["time"]
+	}
+
+	if tz, ok := opts["tz"]; ok {
+		c.location, _ = time.LoadLocation(tz) // an unknown zone is ignored
+	}
+
+	if sep, ok := opts["join"]; ok {
+		c.separator = sep
+	}
+
+	if opts.has("wide") {
+		c.tier = "wide"
+	}
+
+	c.styles = parseStyleRules(opts["color"])
+
+	for _, f := range t.footers {
+		if c.matches(f.column) {
+			c.Aggregate = f.fn
+		}
+	}
+
+	if c.Align == AlignAuto && t.alignNumbers {
+		c.Align = numericAlignment(c)
+	}
+
+	return c
+}
+
+// hasExportedFields returns true if the struct type has an exported field. An
+// embedded struct without any, such as time.Time, is a single column.
+func hasExportedFields(typ reflect.Type) bool {
+	for i := range typ.NumField() {
+		if typ.Field(i).IsExported() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// tagOptions are the options following the label in a "table" struct tag.
+// Options are either flags, such as "omitempty", or name=value pairs, such as
+// "align=right". The value of "join" is the rest of the tag, since the
+// separator can contain commas and spaces, so it must be the last option.
+type tagOptions map[string]string
+
+func parseTagOptions(s string) tagOptions {
+	opts := tagOptions{}
+
+	for s != "" {
+		var o string
+
+		if strings.HasPrefix(strings.TrimSpace(s), "join=") {
+			_, opts["join"], _ = strings.Cut(s, "=")
+
+			break
+		}
+
+		o, s, _ = strings.Cut(s, ",")
+
+		name, value, _ := strings.Cut(o, "=")
+		if name = strings.TrimSpace(name); name != "" {
+			opts[name] = strings.TrimSpace(value)
+		}
+	}
+
+	return opts
+}
+
+func (o tagOptions) has(name string) bool {
+	_, ok := o[name]
+
+	return ok
+}
+
+func findRepeats(top, bottom []Cell) []bool {
 	r := make([]bool, len(bottom))
 
 	if top == nil || len(top) != len(bottom) {
@@ -241,9 +705,37 @@ func findRepeats(top, bottom []cell) []bool {
 	return r
 }
 
-func isColumnZero(n int, rows [][]cell) bool {
+// markEmptyColumns flags the omitempty columns where every value is zero or
+// is formatted as empty text.
+func markEmptyColumns(info []Column, rows [][]Cell) {
+	for j := range info {
+		if info[j].OmitEmpty && isColumnZero(j, rows) {
+			info[j].IsZero = true
+		}
+	}
+}
+
+// isNumeric returns true if the column holds integer or floating point values.
+func (c Column) isNumeric() bool {
+	switch c.Kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// matches returns true if name is the first header label or the struct field
+// name of the column.
+func (c Column) matches(name string) bool {
+	return c.Labels[0] == name || c.Name == name
+}
+
+func isColumnZero(n int, rows [][]Cell) bool {
 	for i := range rows {
-		if !rows[i][n].Value.IsZero() {
+		if v := rows[i][n].Value; v.IsValid() && !v.IsZero() && rows[i][n].Text != "" {
 			return false
 		}
 	}
@@ -0,0 +1,168 @@
+package table
+
+import (
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// units scales numbers into human readable units.
+type units struct {
+	base      float64  // base of the scale, zero for percentages
+	suffixes  []string // suffixes for each power of the base
+	separator string   // separator between the number and the suffix
+}
+
+var (
+	iecBytes = units{base: 1024, suffixes: []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}, separator: " "}
+	siBytes  = units{base: 1000, suffixes: []string{"B", "kB", "MB", "GB", "TB", "PB", "EB"}, separator: " "}
+	siCount  = units{base: 1000, suffixes: []string{"", "k", "M", "G", "T", "P", "E"}}
+	percent  = units{suffixes: []string{"%"}}
+)
+
+// parseUnits returns the units of the "bytes", "bytes=si", "count=si" and
+// "percent" tag options, or nil if there are none.
+func parseUnits(opts tagOptions) *units {
+	switch {
+	case opts.has("bytes") && opts["bytes"] == "si":
+		return &siBytes
+	case opts.has("bytes"):
+		return &iecBytes
+	case opts["count"] == "si":
+		return &siCount
+	case opts.has("percent"):
+		return &percent
+	default:
+		return nil
+	}
+}
+
+// power returns the power of the base used to show x.
+func (u *units) power(x float64) int {
+	if u.base == 0 {
+		return 0
+	}
+
+	p := 0
+	for x = math.Abs(x); x >= u.base && p < len(u.suffixes)-1; x /= u.base {
+		p++
+	}
+
+	return p
+}
+
+// format returns x in the units of the power p, without the suffix. Unless
+// prec is not negative, scaled numbers and percentages have one decimal place
+// and whole numbers that are not scaled have none.
+func (u *units) format(x float64, p, prec int) string {
+	if u.base == 0 {
+		x *= 100
+	} else {
+		x /= math.Pow(u.base, float64(p))
+	}
+
+	if prec < 0 {
+		prec = 1
+		if p == 0 && u.base != 0 && x == math.Trunc(x) {
+			prec = 0
+		}
+	}
+
+	return strconv.FormatFloat(x, 'f', prec, 64)
+}
+
+// suffix returns the suffix of the power p with its separator.
+func (u *units) suffix(p int) string {
+	if u.base == 0 || u.suffixes[p] == "" {
+		return u.suffixes[p]
+	}
+
+	return u.separator + u.suffixes[p]
+}
+
+// scaled returns x in the units of the power p, with the locale and "prec" of
+// the column, and the unit suffix if suffix is true.
+func (c Column) scaled(x float64, p int, suffix bool) string {
+	s := c.locale.number(c.units.format(x, p, c.precision))
+	if suffix {
+		s += c.units.suffix(p)
+	}
+
+	return s
+}
+
+// number returns the value of v as a float64 if it is a number.
+func number(v reflect.Value) (float64, bool) {
+	switch {
+	case !v.IsValid():
+		return 0, false
+	case v.CanInt():
+		return float64(v.Int()), true
+	case v.CanUint():
+		return float64(v.Uint()), true
+	case v.CanFloat():
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// scaleColumns shows the numbers of each column with the "scale=column" tag
+// option in the one unit that fits its largest value, and adds the unit to the
+// header as a second line.
+func scaleColumns(s *Section) {
+	rows := slices.Clip(s.Cells)
+
+	for _, g := range s.Groups {
+		if g.Footer != nil {
+			rows = append(rows, g.Footer)
+		}
+	}
+
+	if s.Footer != nil {
+		rows = append(rows, s.Footer)
+	}
+
+	for j := range s.Columns {
+		c := &s.Columns[j]
+		if c.units == nil || !c.scaleColumn {
+			continue
+		}
+
+		var largest float64
+
+		for _, row := range rows {
+			if x, ok := number(row[j].Value); ok {
+				largest = max(largest, math.Abs(x))
+			}
+		}
+
+		p := c.units.power(largest)
+		c.unit = c.units.suffixes[p]
+
+		if c.unit != "" {
+			c.Labels = append(slices.Clip(c.Labels), "("+c.unit+")")
+		}
+
+		c.Width = maxStringLength(c.Labels)
+
+		for _, row := range rows {
+			if x, ok := number(row[j].Value); ok {
+				row[j].Text = c.scaled(x, p, false)
+			}
+
+			c.Width = max(c.Width, displayWidth(row[j].Text))
+		}
+	}
+}
+
+// valueLabels returns the header labels without the unit line added by
+// scaleColumns, since JSON and YAML encode the unscaled values.
+func (c Column) valueLabels() []string {
+	if c.unit != "" {
+		return c.Labels[:len(c.Labels)-1]
+	}
+
+	return c.Labels
+}
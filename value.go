@@ -0,0 +1,215 @@
+package table
+
+import (
+	"encoding"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultJoin separates the elements of slices, arrays and maps.
+const defaultJoin = ", "
+
+// timeLayouts are the names of the layouts for the "time" tag option. Other
+// values of the option are used as the layout.
+var timeLayouts = map[string]string{
+	"rfc3339":     time.RFC3339,
+	"rfc3339nano": time.RFC3339Nano,
+	"rfc1123":     time.RFC1123,
+	"rfc822":      time.RFC822,
+	"kitchen":     time.Kitchen,
+	"stamp":       time.Stamp,
+	"date":        time.DateOnly,
+	"datetime":    time.DateTime,
+	"relative":    "relative",
+}
+
+// formatterInterfaces are the interfaces used to format a value. A pointer implementing
+// one of them is not dereferenced, unless the value it points to implements it
+// too.
+var formatterInterfaces = []reflect.Type{
+	reflect.TypeFor[wrapper](),
+	reflect.TypeFor[error](),
+	reflect.TypeFor[fmt.Stringer](),
+	reflect.TypeFor[encoding.TextMarshaler](),
+}
+
+// now is replaced by tests of relative times.
+var now = time.Now
+
+// indirect dereferences the pointers and interfaces of v, and unwraps the
+// database/sql Null types. It returns the zero Value if one of them is nil or
+// not Valid.
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+
+		if v.Kind() == reflect.Pointer && isFormatter(v.Type()) && !isFormatter(v.Type().Elem()) {
+			return v
+		}
+
+		v = v.Elem()
+	}
+
+	if typ := v.Type(); typ.PkgPath() == "database/sql" && strings.HasPrefix(typ.Name(), "Null") {
+		if valid := v.FieldByName("Valid"); valid.IsValid() && !valid.Bool() {
+			return reflect.Value{}
+		}
+
+		return indirect(v.Field(0))
+	}
+
+	return v
+}
+
+func isFormatter(typ reflect.Type) bool {
+	return slices.ContainsFunc(formatterInterfaces, typ.Implements)
+}
+
+// text returns the cell text of the value v of column c. The elements of
+// slices, arrays and maps are joined, with maps shown as key=value pairs
+// sorted by key. Times and durations use the "time", "tz" and "round" options.
+// Numbers in a column with units are scaled, and then types with a formatter
+// from WithFormatter or RegisterFormatter use it. Numbers use the locale and
+// the "prec" option, and floats are shown without an exponent unless they are
+// very small or large.
+func (c Column) text(v reflect.Value) string {
+	if !v.IsValid() || !v.CanInterface() { // unexported fields are empty
+		return ""
+	}
+
+	if x, ok := number(v); ok && c.units != nil {
+		return c.scaled(x, c.units.power(x), true)
+	}
+
+	if fn := c.formatter(v.Type()); fn != nil {
+		return fn(v)
+	}
+
+	switch a := v.Interface().(type) {
+	case time.Time:
+		return c.formatTime(a)
+	case time.Duration:
+		return a.Round(c.round).String()
+	}
+
+	if (c.locale != nil || c.precision >= 0 || v.CanFloat()) && !isFormatter(v.Type()) {
+		if s, ok := c.formatNumber(v); ok {
+			return s
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 { // []byte is not a list
+			break
+		}
+
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = c.text(indirect(v.Index(i)))
+		}
+
+		return c.join(items)
+	case reflect.Map:
+		keys := v.MapKeys()
+		slices.SortFunc(keys, compareValues)
+
+		items := make([]string, len(keys))
+		for i, k := range keys {
+			items[i] = c.text(k) + "=" + c.text(indirect(v.MapIndex(k)))
+		}
+
+		return c.join(items)
+	}
+
+	return valueAsString(v)
+}
+
+// formatFloat formats x in the shortest text, like encoding/json: without an
+// exponent unless it is less than 1e-6 or at least 1e21.
+func formatFloat(x float64, bits int) string {
+	if a := math.Abs(x); a != 0 && (a < 1e-6 || a >= 1e21) {
+		return strconv.FormatFloat(x, 'g', -1, bits)
+	}
+
+	return strconv.FormatFloat(x, 'f', -1, bits)
+}
+
+// join joins the elements of a list with the "join" separator, or one per line
+// if the column has the "explode" option. Elements over the "limit" are counted
+// instead of shown.
+func (c Column) join(items []string) string {
+	var more string
+
+	if c.limit > 0 && len(items) > c.limit {
+		more = "+" + strconv.Itoa(len(items)-c.limit) + " more"
+		items = items[:c.limit]
+	}
+
+	if c.explode {
+		if more != "" {
+			items = append(items, more)
+		}
+
+		return strings.Join(items, "\n")
+	}
+
+	s := strings.Join(items, c.separator)
+	if more != "" {
+		s += " " + more
+	}
+
+	return s
+}
+
+// formatTime formats t with the layout and location of the column. The zero
+// time is empty.
+func (c Column) formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	if c.location != nil {
+		t = t.In(c.location)
+	}
+
+	switch c.layout {
+	case "":
+		return t.Format(time.RFC3339)
+	case "relative":
+		return relativeTime(t)
+	default:
+		return t.Format(c.layout)
+	}
+}
+
+// relativeTime returns the time from now to t in the largest whole unit, such
+// as "5m ago" or "in 2d".
+func relativeTime(t time.Time) string {
+	d := now().Sub(t)
+
+	format := "%d%s ago"
+	if d < 0 {
+		d, format = -d, "in %d%s"
+	}
+
+	switch {
+	case d < time.Second:
+		return "now"
+	case d < time.Minute:
+		return fmt.Sprintf(format, d/time.Second, "s")
+	case d < time.Hour:
+		return fmt.Sprintf(format, d/time.Minute, "m")
+	case d < 48*time.Hour:
+		return fmt.Sprintf(format, d/time.Hour, "h")
+	default:
+		return fmt.Sprintf(format, d/(24*time.Hour), "d")
+	}
+}
@@ -0,0 +1,143 @@
+package table
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"endobit.io/table/sgr"
+)
+
+// WithVertical is an option setting function for New. It prints each row of
+// the text output as a record: a block of "LABEL value" lines under a record
+// separator. This is easier to read for structs with many fields.
+func WithVertical() func(*Table) {
+	return func(t *Table) {
+		t.vertical = true
+	}
+}
+
+// WithAutoVertical is an option setting function for New. It prints a table of
+// the text output as records, like WithVertical, only if it is wider than the
+// maximum width set by WithMaxWidth or the width of the terminal.
+func WithAutoVertical() func(*Table) {
+	return func(t *Table) {
+		t.autoVertical = true
+	}
+}
+
+// isVertical returns true if the section should be printed as records when the
+// output is at most width wide.
+func (t *Table) isVertical(s Section, width int) bool {
+	if t.vertical {
+		return true
+	}
+
+	return t.autoVertical && width > 0 && tableWidth(s.Columns, false, t.separatorWidth())+t.frameWidth() > width
+}
+
+func (t *Table) flushVertical(w io.Writer, s Section) {
+	var labelWidth, valueWidth int
+
+	labels := make([]string, len(s.Columns))
+
+	for j, c := range s.Columns {
+		if c.visible() {
+			labels[j] = strings.Join(c.Labels, " ")
+			labelWidth = max(labelWidth, displayWidth(labels[j]))
+			valueWidth = max(valueWidth, c.Width)
+		}
+	}
+
+	rule := func(title string) {
+		separator := "-[ " + title + " ]"
+		fmt.Fprintln(w, separator+strings.Repeat("-", max(labelWidth+1+valueWidth-displayWidth(separator), 0)))
+	}
+
+	totals := func(title string, footer []Cell) {
+		rule(title)
+
+		for j, cell := range footer {
+			if s.Columns[j].visible() && s.Columns[j].Aggregate != nil {
+				label := sgr.Wrap(t.colors.Header, labels[j], strings.Repeat(" ", labelWidth-displayWidth(labels[j])))
+
+				fmt.Fprintln(w, label, sgr.Wrap(t.colors.Footer, cell.Text))
+			}
+		}
+	}
+
+	annotations, groups := s.Annotations, s.Groups
+
+	for i, row := range s.Cells {
+		for len(annotations) > 0 && annotations[0].Index == i {
+			fmt.Fprintln(w, sgr.Wrap(t.colors.Annotation, annotations[0].Text))
+			annotations = annotations[1:]
+		}
+
+		if len(groups) > 0 && groups[0].Start == i {
+			fmt.Fprintln(w, sgr.Wrap(t.colors.Group, strings.Join(groups[0].Labels, ", ")))
+		}
+
+		rule("RECORD " + strconv.Itoa(i+1))
+
+		for j, cell := range row {
+			if !s.Columns[j].visible() {
+				continue
+			}
+
+			label := sgr.Wrap(t.colors.Header, labels[j], strings.Repeat(" ", labelWidth-displayWidth(labels[j])))
+
+			for k, text := range t.verticalLines(s.Columns[j], cell) {
+				if k > 0 {
+					label = sgr.Wrap(nil, strings.Repeat(" ", labelWidth))
+				}
+
+				fmt.Fprintln(w, label, text)
+			}
+		}
+
+		if len(groups) > 0 && groups[0].End == i+1 {
+			if groups[0].Footer != nil {
+				totals(subtotalLabel, groups[0].Footer)
+			}
+
+			groups = groups[1:]
+		}
+	}
+
+	for _, a := range annotations {
+		fmt.Fprintln(w, sgr.Wrap(t.colors.Annotation, a.Text))
+	}
+
+	if s.Footer != nil {
+		totals(footerLabel, s.Footer)
+	}
+}
+
+// verticalLines returns the styled lines of the cell for a record.
+func (t *Table) verticalLines(c Column, cell Cell) []string {
+	if cell.Text == "" {
+		return []string{sgr.Wrap(t.colors.Empty, "-").String()}
+	}
+
+	lines := strings.Split(cell.Text, "\n")
+
+	if t.noColor {
+		return lines
+	}
+
+	if style := t.style(c, cell); style != nil {
+		for i := range lines {
+			lines[i] = sgr.Wrap(style, lines[i]).String()
+		}
+
+		return lines
+	}
+
+	if a, ok := cell.wrapper(); ok {
+		return strings.Split(a.Wrap().String(), "\n")
+	}
+
+	return lines
+}
@@ -1,12 +1,16 @@
 package table
 
-import "github.com/goccy/go-yaml"
+import (
+	"io"
+
+	"github.com/goccy/go-yaml"
+)
 
 // AsYAML is an option setting function for New. It sets YAML as the default
 // output format for Flush.
 func AsYAML() func(*Table) {
 	return func(t *Table) {
-		t.style = yamlOutput
+		t.format = FormatYAML
 	}
 }
 
@@ -21,7 +25,21 @@ func NewYAML(opts ...func(*Table)) *Table {
 
 // FlushYAML flushes the Table data to its io.Writer as YAML.
 func (t *Table) FlushYAML() error {
-	e := yaml.NewEncoder(t.writer)
+	if t.err != nil {
+		return t.err
+	}
+
+	return t.encodeYAML(t.writer, t.sections())
+}
+
+func (t *Table) encodeYAML(w io.Writer, sections []Section) error {
+	var opts []yaml.EncodeOption
+
+	if t.envelope {
+		opts = append(opts, yaml.WithComment(yamlComments(sections)))
+	}
+
+	e := yaml.NewEncoder(w, opts...)
 
-	return e.Encode(t.rows)
+	return e.Encode(t.encodable(sections))
 }